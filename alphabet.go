@@ -0,0 +1,242 @@
+// Use of this source code is governed by the CC0 1.0
+// license that can be found in the LICENSE file or here:
+// http://creativecommons.org/publicdomain/zero/1.0/
+
+package base
+
+import (
+	"fmt"
+	"math/big"
+	"strconv"
+)
+
+// Alphabet is an ordered set of distinct ASCII characters where the character at index i represents the digit i.
+//
+// Alphabets are immutable once created and safe for concurrent use.
+type Alphabet struct {
+	chars string
+	dec   [256]int16
+	// name is the base number of the alphabets of Digits, see Event.
+	name string
+}
+
+// NewAlphabet returns an Alphabet using the characters in chars as digits, the first character is digit 0.
+//
+// chars must contain between 2 and 128 distinct ASCII characters.
+func NewAlphabet(chars string) (*Alphabet, error) {
+	if len(chars) < 2 || len(chars) > 128 {
+		return nil, fmt.Errorf("Illegal alphabet size %d.", len(chars))
+	}
+	a := &Alphabet{chars: chars}
+	for i := range a.dec {
+		a.dec[i] = -1
+	}
+	for i := 0; i < len(chars); i++ {
+		c := chars[i]
+		if c >= 0x80 {
+			return nil, fmt.Errorf("Illegal non ASCII character %q in alphabet.", c)
+		}
+		if a.dec[c] >= 0 {
+			return nil, fmt.Errorf("Illegal duplicate character %q in alphabet.", c)
+		}
+		a.dec[c] = int16(i)
+	}
+	return a, nil
+}
+
+// Digits returns the Alphabet used by Encode and Decode for base b.
+//
+// Just like Decode the returned Alphabet accepts upper case letters as an alternative spelling of lower case letters when b is 36 or less.
+func Digits(b int) (*Alphabet, error) {
+	if b < 2 || b > len(digits) {
+		return nil, fmt.Errorf("Illegal base %d.", b)
+	}
+	a, err := NewAlphabet(digits[:b])
+	if err != nil {
+		return nil, err
+	}
+	if b <= 36 {
+		a.foldCase()
+	}
+	a.name = strconv.Itoa(b)
+	return a, nil
+}
+
+// Len returns the number of digits in a, i.e. the base of a.
+func (a *Alphabet) Len() int {
+	return len(a.chars)
+}
+
+// String returns the characters of a in digit order.
+func (a *Alphabet) String() string {
+	return a.chars
+}
+
+// Index returns the digit value of c or -1 if c is not part of a.
+func (a *Alphabet) Index(c byte) int {
+	return int(a.dec[c])
+}
+
+// EncodeInt returns the representation of the non negative integer n using the digits of a.
+//
+// Unlike Encode, zero is represented by a single zero digit.
+func (a *Alphabet) EncodeInt(n *big.Int) ([]byte, error) {
+	if n.Sign() < 0 {
+		return nil, fmt.Errorf("Illegal negative integer.")
+	}
+	if n.Sign() == 0 {
+		return []byte{a.chars[0]}, nil
+	}
+
+	// Divide by the largest power of the base that fits in a word and
+	// convert each remainder using machine arithmetic.
+	k := uint64(len(a.chars))
+	chunk, width := chunkSize(k)
+	q := new(big.Int).Set(n)
+	m := new(big.Int).SetUint64(chunk)
+	rem := new(big.Int)
+
+	d := make([]byte, 0, n.BitLen()/2+width)
+	for q.Sign() > 0 {
+		q.QuoRem(q, m, rem)
+		r := rem.Uint64()
+		for j := 0; j < width; j++ {
+			if q.Sign() == 0 && r == 0 {
+				break
+			}
+			d = append(d, a.chars[r%k])
+			r /= k
+		}
+	}
+	for i, j := 0, len(d)-1; i < j; i, j = i+1, j-1 {
+		d[i], d[j] = d[j], d[i]
+	}
+	return d, nil
+}
+
+// DecodeInt returns the integer represented by u using the digits of a.
+//
+// u may not contain characters outside of a, an empty u decodes to zero.
+func (a *Alphabet) DecodeInt(u []byte) (*big.Int, error) {
+	k := uint64(len(a.chars))
+	chunk, width := chunkSize(k)
+	n := new(big.Int)
+	t := new(big.Int)
+	m := new(big.Int).SetUint64(chunk)
+
+	for i := 0; i < len(u); i += width {
+		end := i + width
+		if end > len(u) {
+			end = len(u)
+		}
+		var r, p uint64 = 0, 1
+		for _, c := range u[i:end] {
+			v := a.dec[c]
+			if v < 0 {
+				return nil, fmt.Errorf("Illegal character %q in alphabet decoding.", c)
+			}
+			r = r*k + uint64(v)
+			p *= k
+		}
+		if p == chunk {
+			n.Mul(n, m)
+		} else {
+			n.Mul(n, t.SetUint64(p))
+		}
+		n.Add(n, t.SetUint64(r))
+	}
+	return n, nil
+}
+
+// Sorted reports whether the digits of a are in ascending byte order.
+//
+// Only sorted alphabets preserve the numeric order of fixed width encodings when the encoded strings are compared byte by byte.
+func (a *Alphabet) Sorted() bool {
+	for i := 1; i < len(a.chars); i++ {
+		if a.chars[i-1] >= a.chars[i] {
+			return false
+		}
+	}
+	return true
+}
+
+// EncodeFixed is like EncodeInt but pads the result with leading zero digits to exactly width digits.
+//
+// EncodeFixed returns an error if n needs more than width digits.
+func (a *Alphabet) EncodeFixed(n *big.Int, width int) ([]byte, error) {
+	d, err := a.EncodeInt(n)
+	if err != nil {
+		return nil, err
+	}
+	if len(d) > width {
+		return nil, fmt.Errorf("Integer does not fit in %d digits.", width)
+	}
+	r := make([]byte, width)
+	i := width - len(d)
+	for j := 0; j < i; j++ {
+		r[j] = a.chars[0]
+	}
+	copy(r[i:], d)
+	return r, nil
+}
+
+// Width returns the number of digits of a needed to represent every integer with the given number of bits.
+func (a *Alphabet) Width(bits int) int {
+	w := 0
+	for m := new(big.Int).Lsh(big.NewInt(1), uint(bits)); m.Cmp(big.NewInt(1)) > 0; w++ {
+		// Ceiling division by the base
+		m.Add(m, big.NewInt(int64(len(a.chars)-1)))
+		m.Quo(m, big.NewInt(int64(len(a.chars))))
+	}
+	return w
+}
+
+// CaseInsensitive returns a copy of a that also accepts the other case of every letter that is a digit of a.
+//
+// Letters that are digits of a on their own keep their meaning, e.g. Digits(62) is unchanged.
+func (a *Alphabet) CaseInsensitive() *Alphabet {
+	c := *a
+	c.foldCase()
+	return &c
+}
+
+// foldCase makes a accept the other case of every letter that is a digit of a.
+func (a *Alphabet) foldCase() {
+	for c := 'a'; c <= 'z'; c++ {
+		u := c - 'a' + 'A'
+		switch {
+		case a.dec[c] >= 0 && a.dec[u] < 0:
+			a.dec[u] = a.dec[c]
+		case a.dec[u] >= 0 && a.dec[c] < 0:
+			a.dec[c] = a.dec[u]
+		}
+	}
+}
+
+// withAliases returns a copy of a where the first character of every pair in pairs is accepted as an alternative spelling of the second.
+func (a *Alphabet) withAliases(pairs string) *Alphabet {
+	c := *a
+	for i := 0; i+1 < len(pairs); i += 2 {
+		c.dec[pairs[i]] = c.dec[pairs[i+1]]
+	}
+	return &c
+}
+
+// mustAlphabet is like NewAlphabet but panics if chars is not a valid alphabet. It simplifies safe initialization of package level alphabets.
+func mustAlphabet(chars string) *Alphabet {
+	a, err := NewAlphabet(chars)
+	if err != nil {
+		panic(err)
+	}
+	return a
+}
+
+// chunkSize returns the largest power of k that fits in an uint64 together with its exponent.
+func chunkSize(k uint64) (chunk uint64, width int) {
+	chunk = 1
+	for chunk <= (1<<64-1)/k {
+		chunk *= k
+		width++
+	}
+	return chunk, width
+}
@@ -0,0 +1,90 @@
+package base_test
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/7i/base"
+)
+
+func TestNewAlphabet(t *testing.T) {
+	for _, chars := range []string{"", "a", "abca", "ab\xff"} {
+		if _, err := base.NewAlphabet(chars); err == nil {
+			t.Errorf("NewAlphabet(%q) succeeded, expected an error.", chars)
+		}
+	}
+	a, err := base.NewAlphabet("01")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if a.Len() != 2 || a.String() != "01" || a.Index('1') != 1 || a.Index('2') != -1 {
+		t.Errorf("NewAlphabet(\"01\") returned an unexpected alphabet %q.", a)
+	}
+}
+
+func TestAlphabetInt(t *testing.T) {
+	for b := 2; b < 63; b++ {
+		a, err := base.Digits(b)
+		if err != nil {
+			t.Fatal(err)
+		}
+		for _, n := range []*big.Int{big.NewInt(0), big.NewInt(1), big.NewInt(int64(b)), new(big.Int).SetBytes(decodedFF), new(big.Int).SetBytes(decodedRnd)} {
+			res, err := a.EncodeInt(n)
+			if err != nil {
+				t.Fatal(err)
+			}
+			// The package Encode omits the zero digit for zero.
+			if exp, _ := base.Encode(n.Bytes(), b); n.Sign() != 0 && string(res) != string(exp) {
+				t.Errorf("EncodeInt failed for base %d, got: \n%s \nexpected: \n%s.", b, res, exp)
+			}
+			dec, err := a.DecodeInt(res)
+			if err != nil || dec.Cmp(n) != 0 {
+				t.Errorf("DecodeInt failed for base %d, got: %v %v expected: %v.", b, dec, err, n)
+			}
+		}
+	}
+}
+
+func TestDigitsCase(t *testing.T) {
+	a, _ := base.Digits(36)
+	n, err := a.DecodeInt([]byte("Zz"))
+	if err != nil || n.Int64() != 35*36+35 {
+		t.Errorf("Digits(36) did not accept upper case, got: %v %v.", n, err)
+	}
+	a, _ = base.Digits(62)
+	n, err = a.DecodeInt([]byte("Z"))
+	if err != nil || n.Int64() != 61 {
+		t.Errorf("Digits(62) did not decode Z as 61, got: %v %v.", n, err)
+	}
+	if _, err := a.DecodeInt([]byte("-")); err == nil {
+		t.Errorf("DecodeInt accepted an illegal character.")
+	}
+	if _, err := base.Digits(63); err == nil {
+		t.Errorf("Digits(63) succeeded, expected an error.")
+	}
+}
+
+func TestCaseInsensitive(t *testing.T) {
+	// Letters of either case are folded to the other case, so an upper case only alphabet also accepts lower case.
+	upper, _ := base.NewAlphabet("0123456789ABCDEF")
+	a := upper.CaseInsensitive()
+	for _, s := range []string{"fF", "Ff", "ff", "FF"} {
+		n, err := a.DecodeInt([]byte(s))
+		if err != nil || n.Int64() != 255 {
+			t.Errorf("CaseInsensitive DecodeInt(%s) got: %v %v expected: 255.", s, n, err)
+		}
+	}
+	if _, err := upper.DecodeInt([]byte("ff")); err == nil {
+		t.Errorf("CaseInsensitive changed the original alphabet.")
+	}
+
+	// Letters that are digits on their own keep their meaning.
+	mixed, _ := base.NewAlphabet("0123456789abcdeF")
+	a = mixed.CaseInsensitive()
+	if n, err := a.DecodeInt([]byte("AEf")); err != nil || n.Int64() != 10*256+14*16+15 {
+		t.Errorf("CaseInsensitive DecodeInt(AEf) got: %v %v.", n, err)
+	}
+	if n, err := a.DecodeInt([]byte("f")); err != nil || n.Int64() != 15 {
+		t.Errorf("CaseInsensitive DecodeInt(f) got: %v %v expected: 15.", n, err)
+	}
+}
@@ -13,6 +13,8 @@ package base
 import (
 	"fmt"
 	"math/big"
+	"strconv"
+	"time"
 )
 
 const digits = "0123456789abcdefghijklmnopqrstuvwxyzABCDEFGHIJKLMNOPQRSTUVWXYZ"
@@ -26,6 +28,9 @@ var bufferSizeMultiplier = []float64{0, 0, 8, 5.052, 4, 3.452, 3.097, 2.852, 2.6
 //
 // Take note that Encode will remove any null bytes in the start of u, if this is important for your application save original size of the raw string.
 func Encode(u []byte, b int) (r []byte, err error) {
+	if o := loadObserver(); o != nil {
+		defer observe(o, OpEncode, strconv.Itoa(b), len(u), time.Now(), &r, &err)
+	}
 
 	if b < 2 || b > len(digits) {
 		return nil, fmt.Errorf("Illegal Encode base.\n")
@@ -64,6 +69,9 @@ func Encode(u []byte, b int) (r []byte, err error) {
 //
 // Take note that Decode will remove any null bytes in the start of r, if this is important for your application save original size of the raw string.
 func Decode(u []byte, b int) (r []byte, err error) {
+	if o := loadObserver(); o != nil {
+		defer observe(o, OpDecode, strconv.Itoa(b), len(u), time.Now(), &r, &err)
+	}
 
 	if b < 2 || b > len(digits) {
 		return nil, fmt.Errorf("Illegal Decode base.")
@@ -0,0 +1,170 @@
+// Use of this source code is governed by the CC0 1.0
+// license that can be found in the LICENSE file or here:
+// http://creativecommons.org/publicdomain/zero/1.0/
+
+package base
+
+import (
+	"bytes"
+	"encoding/binary"
+	"fmt"
+	"math/big"
+	"net/netip"
+	"time"
+)
+
+// RFC1924 is the base85 alphabet of RFC 1924, registered as "rfc1924". Like Encode it is a positional big number encoding.
+var RFC1924 = mustAlphabet("0123456789ABCDEFGHIJKLMNOPQRSTUVWXYZabcdefghijklmnopqrstuvwxyz!#$%&()*+-;<=>?@^_`{|}~")
+
+// rfc1924Length is the number of base85 digits of a 128 bit IPv6 address.
+const rfc1924Length = 20
+
+// FormatRFC1924 returns the IPv6 address ip in the compact 20 character representation of RFC 1924.
+func FormatRFC1924(ip netip.Addr) (string, error) {
+	if !ip.Is6() {
+		return "", fmt.Errorf("Illegal non IPv6 address %s.", ip)
+	}
+	b := ip.As16()
+	r, err := RFC1924.EncodeFixed(new(big.Int).SetBytes(b[:]), rfc1924Length)
+	return string(r), err
+}
+
+// ParseRFC1924 returns the IPv6 address represented by the 20 character RFC 1924 string s.
+func ParseRFC1924(s string) (netip.Addr, error) {
+	if len(s) != rfc1924Length {
+		return netip.Addr{}, fmt.Errorf("Illegal RFC 1924 address length %d.", len(s))
+	}
+	n, err := RFC1924.DecodeInt([]byte(s))
+	if err != nil {
+		return netip.Addr{}, err
+	}
+	if n.BitLen() > 128 {
+		return netip.Addr{}, fmt.Errorf("RFC 1924 address %q out of range.", s)
+	}
+	var b [16]byte
+	n.FillBytes(b[:])
+	return netip.AddrFrom16(b), nil
+}
+
+// GitBase85 is the base85 encoding of git binary patches, registered as "git85".
+//
+// Every group of 4 bytes is encoded as 5 digits of the RFC1924 alphabet, most significant digit first.
+// A last incomplete group is padded with zero bytes, so Decode returns a multiple of 4 bytes.
+// Git stores the real length in the length character of every line, see EncodeGitBinary.
+var GitBase85 Encoding = gitBase85{}
+
+type gitBase85 struct{}
+
+// Encode returns src encoded like git's encode_85.
+func (g gitBase85) Encode(src []byte) (r []byte) {
+	if o := loadObserver(); o != nil {
+		defer observe(o, OpEncode, eventName(g), len(src), time.Now(), &r, nil)
+	}
+	return g.encode(src)
+}
+
+// Decode returns the data encoded in src like git's decode_85, src must be a multiple of 5 characters.
+func (g gitBase85) Decode(src []byte) (r []byte, err error) {
+	if o := loadObserver(); o != nil {
+		defer observe(o, OpDecode, eventName(g), len(src), time.Now(), &r, &err)
+	}
+	return g.decode(src)
+}
+
+func (gitBase85) encode(src []byte) []byte {
+	r := make([]byte, 0, (len(src)+3)/4*5)
+	for len(src) > 0 {
+		var g [4]byte
+		n := copy(g[:], src)
+		src = src[n:]
+		acc := binary.BigEndian.Uint32(g[:])
+		var d [5]byte
+		for i := 4; i >= 0; i-- {
+			d[i] = RFC1924.chars[acc%85]
+			acc /= 85
+		}
+		r = append(r, d[:]...)
+	}
+	return r
+}
+
+func (gitBase85) decode(src []byte) ([]byte, error) {
+	if len(src)%5 != 0 {
+		return nil, fmt.Errorf("Illegal git base85 length %d.", len(src))
+	}
+	r := make([]byte, 0, len(src)/5*4)
+	for ; len(src) > 0; src = src[5:] {
+		acc := uint32(0)
+		for i, c := range src[:5] {
+			d := RFC1924.dec[c]
+			if d < 0 {
+				return nil, fmt.Errorf("Illegal character %q in git base85 decoding.", c)
+			}
+			// Only the last digit can overflow 32 bits.
+			if i == 4 && (acc > 0xffffffff/85 || 0xffffffff-uint32(d) < acc*85) {
+				return nil, fmt.Errorf("Git base85 group overflow.")
+			}
+			acc = acc*85 + uint32(d)
+		}
+		r = binary.BigEndian.AppendUint32(r, acc)
+	}
+	return r, nil
+}
+
+// gitLineBytes is the number of bytes git encodes in a line of a binary patch.
+const gitLineBytes = 52
+
+// EncodeGitBinary returns data as the lines of a git binary patch hunk. Every line holds up to 52 bytes and starts with
+// a character for the number of bytes in the line, A to Z for 1 to 26 and a to z for 27 to 52, and ends with a newline.
+//
+// Git compresses the data of a binary patch with zlib before encoding it, EncodeGitBinary only does the line encoding.
+func EncodeGitBinary(data []byte) []byte {
+	var b bytes.Buffer
+	for len(data) > 0 {
+		n := min(len(data), gitLineBytes)
+		if n <= 26 {
+			b.WriteByte(byte('A' + n - 1))
+		} else {
+			b.WriteByte(byte('a' + n - 27))
+		}
+		b.Write(GitBase85.Encode(data[:n]))
+		b.WriteByte('\n')
+		data = data[n:]
+	}
+	return b.Bytes()
+}
+
+// DecodeGitBinary returns the data of the lines of a git binary patch hunk encoded by EncodeGitBinary.
+// Decoding stops at the first empty line, which ends a hunk.
+func DecodeGitBinary(lines []byte) ([]byte, error) {
+	var r []byte
+	for len(lines) > 0 {
+		line := lines
+		if i := bytes.IndexByte(lines, '\n'); i >= 0 {
+			line, lines = lines[:i], lines[i+1:]
+		} else {
+			lines = nil
+		}
+		if len(line) == 0 {
+			break
+		}
+		var n int
+		switch c := line[0]; {
+		case 'A' <= c && c <= 'Z':
+			n = int(c-'A') + 1
+		case 'a' <= c && c <= 'z':
+			n = int(c-'a') + 27
+		default:
+			return nil, fmt.Errorf("Illegal git binary line length character %q.", c)
+		}
+		if len(line)-1 != (n+3)/4*5 {
+			return nil, fmt.Errorf("Illegal git binary line length %d for %d bytes.", len(line)-1, n)
+		}
+		d, err := GitBase85.Decode(line[1:])
+		if err != nil {
+			return nil, err
+		}
+		r = append(r, d[:n]...)
+	}
+	return r, nil
+}
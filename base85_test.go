@@ -0,0 +1,118 @@
+package base_test
+
+import (
+	"bytes"
+	"compress/zlib"
+	"io"
+	"net/netip"
+	"testing"
+
+	"github.com/7i/base"
+)
+
+func TestRFC1924(t *testing.T) {
+	// The example from RFC 1924 section 5
+	tests := []struct {
+		ip, exp string
+	}{
+		{"1080:0:0:0:8:800:200C:417A", "4)+k&C#VzJ4br>0wv%Yp"},
+		{"::", "00000000000000000000"},
+		{"::1", "00000000000000000001"},
+		{"ffff:ffff:ffff:ffff:ffff:ffff:ffff:ffff", "=r54lj&NUUO~Hi%c2ym0"},
+	}
+	for _, test := range tests {
+		ip := netip.MustParseAddr(test.ip)
+		res, err := base.FormatRFC1924(ip)
+		if err != nil || res != test.exp {
+			t.Errorf("FormatRFC1924(%s) got: %q %v expected: %q.", test.ip, res, err, test.exp)
+		}
+		back, err := base.ParseRFC1924(test.exp)
+		if err != nil || back != ip {
+			t.Errorf("ParseRFC1924(%q) got: %v %v expected: %s.", test.exp, back, err, ip)
+		}
+	}
+
+	if _, err := base.FormatRFC1924(netip.MustParseAddr("10.0.0.1")); err == nil {
+		t.Errorf("FormatRFC1924 accepted an IPv4 address.")
+	}
+	for _, s := range []string{"4)+k&C#VzJ4br>0wv%Y", "4)+k&C#VzJ4br>0wv%Y\"", "=r54lj&NUUO~Hi%c2ym1"} {
+		if _, err := base.ParseRFC1924(s); err == nil {
+			t.Errorf("ParseRFC1924(%q) succeeded, expected an error.", s)
+		}
+	}
+
+	// Positional encoding like Encode with its own alphabet
+	b85, _ := base.Lookup("rfc1924")
+	if res := b85.Encode([]byte{0x01, 0x00}); string(res) != "31" {
+		t.Errorf("RFC1924.Encode(256) got: %s expected: 31.", res)
+	}
+}
+
+// Binary patch hunks produced by git diff --binary, the payload of every hunk is zlib compressed.
+var gitBinaryTests = []struct {
+	lines string
+	data  []byte
+}{
+	{"HcmV?d00001\n", []byte{}},
+	{"Ncmc~u&B@7U000O<0u=xN\n", []byte("hello\x00")},
+	{"zcmV+l0rma>0RjUA1qKHQ2?`4g4Gs?w5fT#=6&4p585$cL9UdPbAtECrB_<~*DJm;0\n" +
+		"zEiNxGF)}kWH8wXmIXXK$Jw87`K|(`BMMg(RNlHshO-@fxQBqS>RaRG6Sz23MU0z>c\n" +
+		"zVPa!sWoBn+X=-b1ZEkOHadLBXb#`}nd3t+%eSUv{fr5jCg@%WSiHeJijgF6yk&=^?\n" +
+		"zm6n&7nVOrNot~edp`xRtrKYE-sj922t*)=Iv9hzYwYImoxw^Z&y}rM|!NSAD#m2|T\n" +
+		"z$;!*j&Cbuz(bCh@)z;V8+1lIO-QM5e;o{@u<>u$;>FVq3?e6dJ@$&QZ_4fDp`TG0(\n" +
+		"Q{r>*|00000000000I5rVfdBvi\n", append(allBytes(), make([]byte, 10)...)},
+}
+
+func allBytes() []byte {
+	b := make([]byte, 256)
+	for i := range b {
+		b[i] = byte(i)
+	}
+	return b
+}
+
+func TestGitBinary(t *testing.T) {
+	for _, test := range gitBinaryTests {
+		z, err := base.DecodeGitBinary([]byte(test.lines + "\nliteral 0\n"))
+		if err != nil {
+			t.Fatalf("DecodeGitBinary(%q) got: %v.", test.lines, err)
+		}
+		r, err := zlib.NewReader(bytes.NewReader(z))
+		if err != nil {
+			t.Fatal(err)
+		}
+		data, err := io.ReadAll(r)
+		if err != nil || !bytes.Equal(data, test.data) {
+			t.Errorf("DecodeGitBinary(%q) inflates to: %x %v expected: %x.", test.lines, data, err, test.data)
+		}
+		if res := base.EncodeGitBinary(z); string(res) != test.lines {
+			t.Errorf("EncodeGitBinary got: \n%s expected: \n%s.", res, test.lines)
+		}
+	}
+}
+
+func TestGitBase85(t *testing.T) {
+	for _, data := range [][]byte{{}, {0}, {0xff, 0xff, 0xff, 0xff}, []byte("hello world"), decodedRnd} {
+		enc := base.GitBase85.Encode(data)
+		if len(enc) != (len(data)+3)/4*5 {
+			t.Errorf("GitBase85.Encode(%x) got length %d.", data, len(enc))
+		}
+		dec, err := base.GitBase85.Decode(enc)
+		if err != nil || !bytes.Equal(dec[:len(data)], data) || len(dec)%4 != 0 {
+			t.Errorf("GitBase85.Decode(%s) got: %x %v expected: %x.", enc, dec, err, data)
+		}
+	}
+	if res := base.GitBase85.Encode([]byte{0xff, 0xff, 0xff, 0xff}); string(res) != "|NsC0" {
+		t.Errorf("GitBase85.Encode(ffffffff) got: %s expected: |NsC0.", res)
+	}
+	for _, s := range []string{"|NsC1", "~~~~~", "0000", "0000\""} {
+		if _, err := base.GitBase85.Decode([]byte(s)); err == nil {
+			t.Errorf("GitBase85.Decode(%q) succeeded, expected an error.", s)
+		}
+	}
+	for _, s := range []string{"A0000\n", "B|NsC\n", "+00000\n"} {
+		if _, err := base.DecodeGitBinary([]byte(s)); err == nil {
+			t.Errorf("DecodeGitBinary(%q) succeeded, expected an error.", s)
+		}
+	}
+}
@@ -0,0 +1,181 @@
+// Use of this source code is governed by the CC0 1.0
+// license that can be found in the LICENSE file or here:
+// http://creativecommons.org/publicdomain/zero/1.0/
+
+package base
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Bech32Alphabet is the base32 alphabet of bech32 strings defined in BIP 173. Decoding is case insensitive.
+var Bech32Alphabet = mustAlphabet("qpzry9x8gf2tvdw0s3jn54khce6mua7l").CaseInsensitive()
+
+// Bech32Variant selects the checksum constant of a bech32 string.
+type Bech32Variant int
+
+const (
+	// Bech32 is the original checksum of BIP 173.
+	Bech32 Bech32Variant = iota + 1
+	// Bech32m is the modified checksum of BIP 350.
+	Bech32m
+)
+
+// Bech32MaxLength is the length limit of BIP 173. Lightning invoices and other users of bech32 may exceed it.
+const Bech32MaxLength = 90
+
+const bech32mConst = 0x2bc830a3
+
+// EncodeBech32 returns the bech32 string of the human readable part hrp and the 5 bit values in data, see ConvertBits.
+//
+// The result is lower case, hrp must consist of 1 to 83 ASCII characters between 33 and 126 in a single case.
+func EncodeBech32(hrp string, data []byte, v Bech32Variant) (string, error) {
+	if err := checkBech32HRP(hrp); err != nil {
+		return "", err
+	}
+	if v != Bech32 && v != Bech32m {
+		return "", fmt.Errorf("Illegal bech32 variant %d.", v)
+	}
+	for _, d := range data {
+		if d >= 32 {
+			return "", fmt.Errorf("Illegal 5 bit value %d in bech32 data.", d)
+		}
+	}
+	hrp = strings.ToLower(hrp)
+	values := append(bech32ExpandHRP(hrp), data...)
+	values = append(values, make([]byte, 6)...)
+	c := bech32Polymod(values)
+	if v == Bech32m {
+		c ^= bech32mConst
+	} else {
+		c ^= 1
+	}
+
+	var b strings.Builder
+	b.Grow(len(hrp) + 1 + len(data) + 6)
+	b.WriteString(hrp)
+	b.WriteByte('1')
+	for _, d := range data {
+		b.WriteByte(Bech32Alphabet.chars[d])
+	}
+	for i := 0; i < 6; i++ {
+		b.WriteByte(Bech32Alphabet.chars[c>>uint(5*(5-i))&31])
+	}
+	return b.String(), nil
+}
+
+// DecodeBech32 returns the lower case human readable part and the 5 bit data values of the bech32 string s together with its checksum variant.
+//
+// s may be upper or lower case but not mixed. If maxLen is positive longer strings are rejected, use Bech32MaxLength for BIP 173 strings.
+func DecodeBech32(s string, maxLen int) (hrp string, data []byte, v Bech32Variant, err error) {
+	if maxLen > 0 && len(s) > maxLen {
+		return "", nil, 0, fmt.Errorf("Illegal bech32 length %d, at most %d.", len(s), maxLen)
+	}
+	if strings.ToLower(s) != s && strings.ToUpper(s) != s {
+		return "", nil, 0, fmt.Errorf("Illegal mixed case bech32 string.")
+	}
+	s = strings.ToLower(s)
+	sep := strings.LastIndexByte(s, '1')
+	if sep < 0 || len(s)-sep-1 < 6 {
+		return "", nil, 0, fmt.Errorf("Illegal bech32 string without separator and checksum.")
+	}
+	hrp = s[:sep]
+	if err := checkBech32HRP(hrp); err != nil {
+		return "", nil, 0, err
+	}
+	data = make([]byte, len(s)-sep-1)
+	for i := range data {
+		c := s[sep+1+i]
+		d := Bech32Alphabet.dec[c]
+		if d < 0 {
+			return "", nil, 0, fmt.Errorf("Illegal character %q in bech32 decoding.", c)
+		}
+		data[i] = byte(d)
+	}
+	switch bech32Polymod(append(bech32ExpandHRP(hrp), data...)) {
+	case 1:
+		v = Bech32
+	case bech32mConst:
+		v = Bech32m
+	default:
+		return "", nil, 0, fmt.Errorf("Invalid bech32 checksum.")
+	}
+	return hrp, data[:len(data)-6], v, nil
+}
+
+// ConvertBits regroups the values of data with from bits each into values of to bits each, most significant bit first.
+//
+// If pad is true an incomplete last group is padded with zero bits. Otherwise the remaining bits must be fewer than from and all zero,
+// as required when converting 5 bit bech32 data back to bytes.
+func ConvertBits(data []byte, from, to uint, pad bool) ([]byte, error) {
+	if from < 1 || from > 8 || to < 1 || to > 8 {
+		return nil, fmt.Errorf("Illegal bit group sizes %d and %d.", from, to)
+	}
+	var acc, bits uint
+	maxv := uint(1)<<to - 1
+	r := make([]byte, 0, (len(data)*int(from)+int(to)-1)/int(to))
+	for _, d := range data {
+		if uint(d)>>from != 0 {
+			return nil, fmt.Errorf("Illegal %d bit value %d.", from, d)
+		}
+		acc = acc<<from | uint(d)
+		bits += from
+		for bits >= to {
+			bits -= to
+			r = append(r, byte(acc>>bits&maxv))
+		}
+	}
+	if pad {
+		if bits > 0 {
+			r = append(r, byte(acc<<(to-bits)&maxv))
+		}
+	} else if bits >= from || acc<<(to-bits)&maxv != 0 {
+		return nil, fmt.Errorf("Illegal padding in bit conversion.")
+	}
+	return r, nil
+}
+
+func checkBech32HRP(hrp string) error {
+	if len(hrp) < 1 || len(hrp) > 83 {
+		return fmt.Errorf("Illegal bech32 human readable part length %d.", len(hrp))
+	}
+	if strings.ToLower(hrp) != hrp && strings.ToUpper(hrp) != hrp {
+		return fmt.Errorf("Illegal mixed case bech32 human readable part.")
+	}
+	for i := 0; i < len(hrp); i++ {
+		if hrp[i] < 33 || hrp[i] > 126 {
+			return fmt.Errorf("Illegal character %q in bech32 human readable part.", hrp[i])
+		}
+	}
+	return nil
+}
+
+// bech32ExpandHRP returns the high bits of every character of hrp, a zero and the low bits of every character, as fed to the checksum.
+func bech32ExpandHRP(hrp string) []byte {
+	r := make([]byte, 0, 2*len(hrp)+1)
+	for i := 0; i < len(hrp); i++ {
+		r = append(r, hrp[i]>>5)
+	}
+	r = append(r, 0)
+	for i := 0; i < len(hrp); i++ {
+		r = append(r, hrp[i]&31)
+	}
+	return r
+}
+
+// bech32Polymod computes the BCH checksum of BIP 173 over values.
+func bech32Polymod(values []byte) uint32 {
+	gen := [5]uint32{0x3b6a57b2, 0x26508e6d, 0x1ea119fa, 0x3d4233dd, 0x2a1462b3}
+	chk := uint32(1)
+	for _, v := range values {
+		b := chk >> 25
+		chk = (chk&0x1ffffff)<<5 ^ uint32(v)
+		for i := 0; i < 5; i++ {
+			if b>>uint(i)&1 == 1 {
+				chk ^= gen[i]
+			}
+		}
+	}
+	return chk
+}
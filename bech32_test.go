@@ -0,0 +1,96 @@
+package base_test
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/7i/base"
+)
+
+func TestBech32(t *testing.T) {
+	// Valid test vectors from BIP 173 and BIP 350
+	tests := []struct {
+		s string
+		v base.Bech32Variant
+	}{
+		{"A12UEL5L", base.Bech32},
+		{"a12uel5l", base.Bech32},
+		{"an83characterlonghumanreadablepartthatcontainsthenumber1andtheexcludedcharactersbio1tt5tgs", base.Bech32},
+		{"abcdef1qpzry9x8gf2tvdw0s3jn54khce6mua7lmqqqxw", base.Bech32},
+		{"split1checkupstagehandshakeupstreamerranterredcaperred2y9e3w", base.Bech32},
+		{"?1ezyfcl", base.Bech32},
+		{"A1LQFN3A", base.Bech32m},
+		{"a1lqfn3a", base.Bech32m},
+		{"abcdef1l7aum6echk45nj3s0wdvt2fg8x9yrzpqzd3ryx", base.Bech32m},
+		{"split1checkupstagehandshakeupstreamerranterredcaperredlc445v", base.Bech32m},
+		{"?1v759aa", base.Bech32m},
+	}
+	for _, test := range tests {
+		hrp, data, v, err := base.DecodeBech32(test.s, base.Bech32MaxLength)
+		if err != nil || v != test.v {
+			t.Errorf("DecodeBech32(%s) got: %v %v expected: %v.", test.s, v, err, test.v)
+			continue
+		}
+		res, err := base.EncodeBech32(hrp, data, v)
+		if err != nil || res != strings.ToLower(test.s) {
+			t.Errorf("EncodeBech32(%s, %v) got: %s %v expected: %s.", hrp, data, res, err, strings.ToLower(test.s))
+		}
+	}
+}
+
+func TestBech32Errors(t *testing.T) {
+	// Invalid test vectors from BIP 173 and BIP 350
+	for _, s := range []string{
+		"\x201nwldj5", // hrp character out of range
+		"\x7f1axkwrx", // hrp character out of range
+		"an84characterslonghumanreadablepartthatcontainsthenumber1andtheexcludedcharactersbio1569pvx", // overall max length exceeded
+		"pzry9x0s0muk",  // no separator character
+		"1pzry9x0s0muk", // empty hrp
+		"x1b4n0q5v",     // invalid data character
+		"li1dgmt3",      // too short checksum
+		"de1lg7wt\xff",  // invalid character in checksum
+		"A1G7SGD8",      // checksum calculated with upper case form
+		"10a06t8",       // empty hrp
+		"1qzzfhee",      // empty hrp
+		"A12UEl5L",      // mixed case
+		"a12uel5m",      // wrong checksum
+	} {
+		if _, _, _, err := base.DecodeBech32(s, base.Bech32MaxLength); err == nil {
+			t.Errorf("DecodeBech32(%q) succeeded, expected an error.", s)
+		}
+	}
+
+	if _, err := base.EncodeBech32("a", []byte{32}, base.Bech32); err == nil {
+		t.Errorf("EncodeBech32 accepted a value of more than 5 bits.")
+	}
+	if _, err := base.EncodeBech32("Ab", nil, base.Bech32); err == nil {
+		t.Errorf("EncodeBech32 accepted a mixed case hrp.")
+	}
+}
+
+func TestConvertBits(t *testing.T) {
+	data := []byte{0x00, 0x01, 0x02, 0xfe, 0xff}
+	five, err := base.ConvertBits(data, 8, 5, true)
+	if err != nil || len(five) != 8 {
+		t.Fatalf("ConvertBits(%x, 8, 5) got: %v %v.", data, five, err)
+	}
+	back, err := base.ConvertBits(five, 5, 8, false)
+	if err != nil || !bytes.Equal(back, data) {
+		t.Errorf("ConvertBits(%v, 5, 8) got: %x %v expected: %x.", five, back, err, data)
+	}
+
+	five, _ = base.ConvertBits([]byte{0xff}, 8, 5, true)
+	if exp := []byte{31, 28}; !bytes.Equal(five, exp) {
+		t.Errorf("ConvertBits(ff, 8, 5) got: %v expected: %v.", five, exp)
+	}
+	// Non zero padding and a whole unused 5 bit group are rejected.
+	for _, d := range [][]byte{{31, 29}, {31, 28, 0}} {
+		if _, err := base.ConvertBits(d, 5, 8, false); err == nil {
+			t.Errorf("ConvertBits(%v, 5, 8) succeeded, expected an error.", d)
+		}
+	}
+	if _, err := base.ConvertBits([]byte{32}, 5, 8, true); err == nil {
+		t.Errorf("ConvertBits accepted a value of more than 5 bits.")
+	}
+}
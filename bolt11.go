@@ -0,0 +1,466 @@
+// Use of this source code is governed by the CC0 1.0
+// license that can be found in the LICENSE file or here:
+// http://creativecommons.org/publicdomain/zero/1.0/
+
+package base
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/binary"
+	"fmt"
+	"math"
+	"math/big"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// The tagged field types of BOLT #11 invoices, the 5 bit value of the bech32 character named in each comment.
+const (
+	InvoicePaymentHash        byte = 1  // p
+	InvoiceRouteHint          byte = 3  // r
+	InvoiceFeatures           byte = 5  // 9
+	InvoiceExpiry             byte = 6  // x
+	InvoiceFallback           byte = 9  // f
+	InvoiceDescription        byte = 13 // d
+	InvoicePaymentSecret      byte = 16 // s
+	InvoicePayee              byte = 19 // n
+	InvoiceDescriptionHash    byte = 23 // h
+	InvoiceMinFinalCLTVExpiry byte = 24 // c
+	InvoiceMetadata           byte = 27 // m
+)
+
+const (
+	invoiceTimestampLength = 7
+	invoiceSignatureLength = 104
+	invoiceMaxFieldLength  = 1<<10 - 1
+	msatPerBitcoin         = 100_000_000_000
+)
+
+// Invoice is a BOLT #11 Lightning payment request.
+//
+// Invoice holds the tagged fields in their 5 bit form so that unknown fields and their order survive a ParseInvoice and Encode round trip.
+// The accessor methods decode the known fields as specified, ignoring fields of a known type with an illegal length.
+// An amount that is not written with the largest exact multiplier, e.g. lnbc2500000n for lnbc2500u, is kept as written
+// while Currency and Amount are unchanged, the signature covers the human readable part as written.
+type Invoice struct {
+	// Currency is the currency prefix of the human readable part, e.g. "bc" for bitcoin or "tb" for testnet.
+	Currency string
+	// Amount is the requested amount in millisatoshi, zero if the invoice does not request a specific amount.
+	Amount    uint64
+	Timestamp time.Time
+	Fields    []InvoiceField
+	// Signature is the compact secp256k1 signature r and s of the invoice and RecoveryID its recovery id from 0 to 3.
+	Signature  [64]byte
+	RecoveryID byte
+	// parsedHRP is the human readable part of a parsed invoice if it is not the one hrp builds.
+	parsedHRP string
+}
+
+// InvoiceField is a tagged field of an Invoice, Data holds 5 bit values.
+type InvoiceField struct {
+	Type byte
+	Data []byte
+}
+
+// BytesField returns the field of type t holding b, padded with zero bits to a multiple of 5 bits.
+func BytesField(t byte, b []byte) InvoiceField {
+	d, _ := ConvertBits(b, 8, 5, true)
+	return InvoiceField{t, d}
+}
+
+// IntField returns the field of type t holding x in as few 5 bit values as possible.
+func IntField(t byte, x uint64) InvoiceField {
+	var d []byte
+	for ; x > 0; x >>= 5 {
+		d = append([]byte{byte(x & 31)}, d...)
+	}
+	return InvoiceField{t, d}
+}
+
+// RouteHop is one hop of a private route in an InvoiceRouteHint field.
+type RouteHop struct {
+	PubKey          [33]byte
+	ShortChannelID  uint64
+	FeeBase         uint32
+	FeeProportional uint32
+	CLTVExpiryDelta uint16
+}
+
+const routeHopLength = 51
+
+// RouteHintField returns the InvoiceRouteHint field of the route hops.
+func RouteHintField(hops []RouteHop) InvoiceField {
+	b := make([]byte, 0, len(hops)*routeHopLength)
+	for _, h := range hops {
+		b = append(b, h.PubKey[:]...)
+		b = binary.BigEndian.AppendUint64(b, h.ShortChannelID)
+		b = binary.BigEndian.AppendUint32(b, h.FeeBase)
+		b = binary.BigEndian.AppendUint32(b, h.FeeProportional)
+		b = binary.BigEndian.AppendUint16(b, h.CLTVExpiryDelta)
+	}
+	return BytesField(InvoiceRouteHint, b)
+}
+
+// InvoiceVerifier recovers the public key that signed an invoice. The package does not implement secp256k1,
+// a verifier is typically a thin wrapper around the compact signature recovery of a secp256k1 library.
+type InvoiceVerifier interface {
+	// RecoverPubKey returns the 33 byte compressed public key whose signature of hash is sig with the recovery id recoveryID.
+	RecoverPubKey(hash [32]byte, sig [64]byte, recoveryID byte) ([]byte, error)
+}
+
+// InvoiceSigner creates compact recoverable secp256k1 signatures for Invoice.Sign.
+type InvoiceSigner interface {
+	SignCompact(hash [32]byte) (sig [64]byte, recoveryID byte, err error)
+}
+
+// ParseInvoice returns the BOLT #11 invoice s. The signature is not verified, see Invoice.Verify.
+func ParseInvoice(s string) (*Invoice, error) {
+	hrp, data, v, err := DecodeBech32(s, 0)
+	if err != nil {
+		return nil, err
+	}
+	if v != Bech32 {
+		return nil, fmt.Errorf("Illegal bech32m checksum in invoice.")
+	}
+	inv := &Invoice{}
+	if err := inv.parseHRP(hrp); err != nil {
+		return nil, err
+	}
+	if h, err := inv.hrp(); err != nil || h != hrp {
+		inv.parsedHRP = hrp
+	}
+	if len(data) < invoiceTimestampLength+invoiceSignatureLength {
+		return nil, fmt.Errorf("Invoice too short.")
+	}
+	inv.Timestamp = time.Unix(int64(uint5Int(data[:invoiceTimestampLength])), 0).UTC()
+
+	sig, err := ConvertBits(data[len(data)-invoiceSignatureLength:], 5, 8, false)
+	if err != nil {
+		return nil, err
+	}
+	copy(inv.Signature[:], sig)
+	inv.RecoveryID = sig[64]
+	if inv.RecoveryID > 3 {
+		return nil, fmt.Errorf("Illegal invoice recovery id %d.", inv.RecoveryID)
+	}
+
+	fields := data[invoiceTimestampLength : len(data)-invoiceSignatureLength]
+	for len(fields) > 0 {
+		if len(fields) < 3 {
+			return nil, fmt.Errorf("Truncated invoice field.")
+		}
+		l := int(fields[1])<<5 | int(fields[2])
+		if len(fields) < 3+l {
+			return nil, fmt.Errorf("Truncated invoice field.")
+		}
+		inv.Fields = append(inv.Fields, InvoiceField{fields[0], fields[3 : 3+l]})
+		fields = fields[3+l:]
+	}
+	return inv, nil
+}
+
+// parseHRP sets the currency and amount of inv from the human readable part hrp, "ln" followed by the currency and an optional amount.
+func (inv *Invoice) parseHRP(hrp string) error {
+	if !strings.HasPrefix(hrp, "ln") {
+		return fmt.Errorf("Illegal invoice prefix %q.", hrp)
+	}
+	hrp = hrp[2:]
+	i := strings.IndexAny(hrp, "0123456789")
+	if i < 0 {
+		inv.Currency = hrp
+		return nil
+	}
+	inv.Currency, hrp = hrp[:i], hrp[i:]
+	if inv.Currency == "" {
+		return fmt.Errorf("Illegal invoice without currency.")
+	}
+
+	// The amount is in bitcoin, optionally followed by a multiplier.
+	unit, div := uint64(msatPerBitcoin), uint64(1)
+	switch c := hrp[len(hrp)-1]; c {
+	case 'm':
+		unit = msatPerBitcoin / 1_000
+	case 'u':
+		unit = msatPerBitcoin / 1_000_000
+	case 'n':
+		unit = msatPerBitcoin / 1_000_000_000
+	case 'p':
+		unit, div = 1, 10
+	default:
+		if c < '0' || c > '9' {
+			return fmt.Errorf("Illegal invoice amount multiplier %q.", c)
+		}
+	}
+	if hrp[len(hrp)-1] > '9' {
+		hrp = hrp[:len(hrp)-1]
+	}
+	if hrp == "" || hrp[0] == '0' {
+		return fmt.Errorf("Illegal invoice amount %q.", hrp)
+	}
+	n, err := strconv.ParseUint(hrp, 10, 64)
+	if err != nil {
+		return fmt.Errorf("Illegal invoice amount %q.", hrp)
+	}
+	if n%div != 0 {
+		return fmt.Errorf("Illegal sub millisatoshi invoice amount %sp.", hrp)
+	}
+	if n/div > math.MaxUint64/unit {
+		return fmt.Errorf("Invoice amount %q overflows.", hrp)
+	}
+	inv.Amount = n / div * unit
+	return nil
+}
+
+// hrp returns the human readable part of inv, the parsed one if it still holds the currency and amount of inv,
+// else using the largest multiplier that represents the amount exactly.
+func (inv *Invoice) hrp() (string, error) {
+	if inv.parsedHRP != "" {
+		var p Invoice
+		if p.parseHRP(inv.parsedHRP) == nil && p.Currency == inv.Currency && p.Amount == inv.Amount {
+			return inv.parsedHRP, nil
+		}
+	}
+	if inv.Currency == "" || strings.IndexAny(inv.Currency, "0123456789") >= 0 {
+		return "", fmt.Errorf("Illegal invoice currency %q.", inv.Currency)
+	}
+	hrp := "ln" + inv.Currency
+	a := inv.Amount
+	switch {
+	case a == 0:
+		return hrp, nil
+	case a%msatPerBitcoin == 0:
+		return hrp + strconv.FormatUint(a/msatPerBitcoin, 10), nil
+	case a%(msatPerBitcoin/1_000) == 0:
+		return hrp + strconv.FormatUint(a/(msatPerBitcoin/1_000), 10) + "m", nil
+	case a%(msatPerBitcoin/1_000_000) == 0:
+		return hrp + strconv.FormatUint(a/(msatPerBitcoin/1_000_000), 10) + "u", nil
+	case a%(msatPerBitcoin/1_000_000_000) == 0:
+		return hrp + strconv.FormatUint(a/(msatPerBitcoin/1_000_000_000), 10) + "n", nil
+	}
+	return hrp + strconv.FormatUint(a, 10) + "0p", nil
+}
+
+// data returns the 5 bit timestamp and tagged fields of inv, without the signature.
+func (inv *Invoice) data() ([]byte, error) {
+	ts := inv.Timestamp.Unix()
+	if ts < 0 || ts >= 1<<35 {
+		return nil, fmt.Errorf("Invoice timestamp %v out of range.", inv.Timestamp)
+	}
+	d := make([]byte, invoiceTimestampLength, 1024)
+	for i := range d {
+		d[i] = byte(ts >> uint(5*(invoiceTimestampLength-1-i)) & 31)
+	}
+	for _, f := range inv.Fields {
+		if f.Type >= 32 || len(f.Data) > invoiceMaxFieldLength {
+			return nil, fmt.Errorf("Illegal invoice field type %d with length %d.", f.Type, len(f.Data))
+		}
+		d = append(d, f.Type, byte(len(f.Data)>>5), byte(len(f.Data)&31))
+		for _, v := range f.Data {
+			if v >= 32 {
+				return nil, fmt.Errorf("Illegal 5 bit value %d in invoice field.", v)
+			}
+		}
+		d = append(d, f.Data...)
+	}
+	return d, nil
+}
+
+// SigningHash returns the SHA-256 hash that the signature of inv signs, the human readable part followed by the data converted to bytes.
+func (inv *Invoice) SigningHash() ([32]byte, error) {
+	hrp, err := inv.hrp()
+	if err != nil {
+		return [32]byte{}, err
+	}
+	d, err := inv.data()
+	if err != nil {
+		return [32]byte{}, err
+	}
+	b, _ := ConvertBits(d, 5, 8, true)
+	return sha256.Sum256(append([]byte(hrp), b...)), nil
+}
+
+// Encode returns inv as a bech32 invoice string using the current Signature and RecoveryID, see Sign.
+func (inv *Invoice) Encode() (string, error) {
+	hrp, err := inv.hrp()
+	if err != nil {
+		return "", err
+	}
+	d, err := inv.data()
+	if err != nil {
+		return "", err
+	}
+	if inv.RecoveryID > 3 {
+		return "", fmt.Errorf("Illegal invoice recovery id %d.", inv.RecoveryID)
+	}
+	sig, _ := ConvertBits(append(inv.Signature[:], inv.RecoveryID), 8, 5, true)
+	return EncodeBech32(hrp, append(d, sig...), Bech32)
+}
+
+// Sign sets the signature of inv to the signature of its SigningHash created by s.
+func (inv *Invoice) Sign(s InvoiceSigner) error {
+	h, err := inv.SigningHash()
+	if err != nil {
+		return err
+	}
+	sig, id, err := s.SignCompact(h)
+	if err != nil {
+		return err
+	}
+	if id > 3 {
+		return fmt.Errorf("Illegal invoice recovery id %d.", id)
+	}
+	inv.Signature, inv.RecoveryID = sig, id
+	return nil
+}
+
+// Verify returns the public key of the payee recovered from the signature of inv by v.
+//
+// If inv has an InvoicePayee field, Verify returns an error unless the recovered key matches it.
+func (inv *Invoice) Verify(v InvoiceVerifier) ([]byte, error) {
+	h, err := inv.SigningHash()
+	if err != nil {
+		return nil, err
+	}
+	key, err := v.RecoverPubKey(h, inv.Signature, inv.RecoveryID)
+	if err != nil {
+		return nil, err
+	}
+	if payee := inv.Payee(); payee != nil && !bytes.Equal(payee, key) {
+		return nil, fmt.Errorf("Invoice signature does not match payee %x.", payee)
+	}
+	return key, nil
+}
+
+// field returns the data of the first field of type t with the given number of 5 bit values, any length if length is negative.
+func (inv *Invoice) field(t byte, length int) ([]byte, bool) {
+	for _, f := range inv.Fields {
+		if f.Type == t && (length < 0 || len(f.Data) == length) {
+			return f.Data, true
+		}
+	}
+	return nil, false
+}
+
+// PaymentHash returns the 32 byte payment hash of inv or nil.
+func (inv *Invoice) PaymentHash() []byte {
+	return inv.bytesField(InvoicePaymentHash, 52)
+}
+
+// PaymentSecret returns the 32 byte payment secret of inv or nil.
+func (inv *Invoice) PaymentSecret() []byte {
+	return inv.bytesField(InvoicePaymentSecret, 52)
+}
+
+// DescriptionHash returns the SHA-256 hash of the description of inv or nil.
+func (inv *Invoice) DescriptionHash() []byte {
+	return inv.bytesField(InvoiceDescriptionHash, 52)
+}
+
+// Payee returns the 33 byte public key of the payee or nil if inv does not name the payee.
+func (inv *Invoice) Payee() []byte {
+	return inv.bytesField(InvoicePayee, 53)
+}
+
+// Metadata returns the payment metadata of inv or nil.
+func (inv *Invoice) Metadata() []byte {
+	return inv.bytesField(InvoiceMetadata, -1)
+}
+
+// Description returns the UTF-8 description of the purpose of the payment.
+func (inv *Invoice) Description() (string, bool) {
+	b := inv.bytesField(InvoiceDescription, -1)
+	return string(b), b != nil
+}
+
+// Expiry returns the time after Timestamp when the invoice expires, one hour if not specified.
+// Expiries longer than the maximum time.Duration, about 292 years, are capped to it.
+func (inv *Invoice) Expiry() time.Duration {
+	if d, ok := inv.field(InvoiceExpiry, -1); ok && len(d) <= 7 {
+		if s := uint5Int(d); s <= math.MaxInt64/uint64(time.Second) {
+			return time.Duration(s) * time.Second
+		}
+		return math.MaxInt64
+	}
+	return time.Hour
+}
+
+// MinFinalCLTVExpiry returns the minimum CLTV expiry delta of the last hop, 18 if not specified.
+func (inv *Invoice) MinFinalCLTVExpiry() uint64 {
+	if d, ok := inv.field(InvoiceMinFinalCLTVExpiry, -1); ok && len(d) <= 12 {
+		return uint5Int(d)
+	}
+	return 18
+}
+
+// Features returns the feature bits of inv, bit i of the result is feature bit i.
+func (inv *Invoice) Features() *big.Int {
+	n := new(big.Int)
+	if d, ok := inv.field(InvoiceFeatures, -1); ok {
+		for _, v := range d {
+			n.Lsh(n, 5).Or(n, big.NewInt(int64(v)))
+		}
+	}
+	return n
+}
+
+// Fallbacks returns the on-chain fallback addresses of inv as witness versions, or 17 and 18 for P2PKH and P2SH, with their programs or hashes.
+func (inv *Invoice) Fallbacks() (versions []byte, programs [][]byte) {
+	for _, f := range inv.Fields {
+		if f.Type == InvoiceFallback && len(f.Data) > 0 {
+			versions = append(versions, f.Data[0])
+			programs = append(programs, uint5Bytes(f.Data[1:]))
+		}
+	}
+	return versions, programs
+}
+
+// RouteHints returns the private routes of all InvoiceRouteHint fields of inv.
+func (inv *Invoice) RouteHints() ([][]RouteHop, error) {
+	var routes [][]RouteHop
+	for _, f := range inv.Fields {
+		if f.Type != InvoiceRouteHint {
+			continue
+		}
+		b := uint5Bytes(f.Data)
+		if len(b) == 0 || len(b)%routeHopLength != 0 {
+			return nil, fmt.Errorf("Illegal invoice route hint length %d.", len(b))
+		}
+		var route []RouteHop
+		for ; len(b) > 0; b = b[routeHopLength:] {
+			var h RouteHop
+			copy(h.PubKey[:], b)
+			h.ShortChannelID = binary.BigEndian.Uint64(b[33:])
+			h.FeeBase = binary.BigEndian.Uint32(b[41:])
+			h.FeeProportional = binary.BigEndian.Uint32(b[45:])
+			h.CLTVExpiryDelta = binary.BigEndian.Uint16(b[49:])
+			route = append(route, h)
+		}
+		routes = append(routes, route)
+	}
+	return routes, nil
+}
+
+func (inv *Invoice) bytesField(t byte, length int) []byte {
+	d, ok := inv.field(t, length)
+	if !ok {
+		return nil
+	}
+	return uint5Bytes(d)
+}
+
+// uint5Int returns the big endian integer of the 5 bit values d.
+func uint5Int(d []byte) uint64 {
+	var x uint64
+	for _, v := range d {
+		x = x<<5 | uint64(v)
+	}
+	return x
+}
+
+// uint5Bytes returns the bytes of the 5 bit values d, dropping the incomplete last byte of padding.
+func uint5Bytes(d []byte) []byte {
+	b, _ := ConvertBits(d, 5, 8, true)
+	return b[:len(d)*5/8]
+}
@@ -0,0 +1,327 @@
+package base_test
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"math"
+	"math/big"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/7i/base"
+)
+
+// The examples of BOLT #11 are signed by this node.
+const (
+	boltPrivKey = "e126f68f7eafcc8b74f54d269fe206be715000f94dac067d1c04a8ca3b2db734"
+	boltPubKey  = "03e7156ae33b0a208d0744199163177e909e80176e55d97a2f221ede0f934dd9ad"
+)
+
+func TestParseInvoice(t *testing.T) {
+	hash, _ := hex.DecodeString("0001020304050607080900010203040506070809000102030405060708090102")
+	secret := bytes.Repeat([]byte{0x11}, 32)
+	cake := sha256.Sum256([]byte("One piece of chocolate cake, one icecream cone, one pickle, one slice of swiss cheese, one slice of salami, one lollypop, one piece of cherry pie, one sausage, one cupcake, and one slice of watermelon"))
+	features := new(big.Int).SetBit(new(big.Int).SetBit(new(big.Int), 8, 1), 14, 1)
+
+	// Examples from BOLT #11
+	tests := []struct {
+		s               string
+		currency        string
+		amount          uint64
+		description     string
+		descriptionHash []byte
+		secret          []byte
+		expiry          time.Duration
+		features        *big.Int
+	}{
+		{"lnbc1pvjluezpp5qqqsyqcyq5rqwzqfqqqsyqcyq5rqwzqfqqqsyqcyq5rqwzqfqypqdpl2pkx2ctnv5sxxmmwwd5kgetjypeh2ursdae8g6twvus8g6rfwvs8qun0dfjkxaq8rkx3yf5tcsyz3d73gafnh3cax9rn449d9p5uxz9ezhhypd0elx87sjle52x86fux2ypatgddc6k63n7erqz25le42c4u4ecky03ylcqca784w",
+			"bc", 0, "Please consider supporting this project", nil, nil, time.Hour, new(big.Int)},
+		{"lnbc2500u1pvjluezpp5qqqsyqcyq5rqwzqfqqqsyqcyq5rqwzqfqqqsyqcyq5rqwzqfqypqdq5xysxxatsyp3k7enxv4jsxqzpuaztrnwngzn3kdzw5hydlzf03qdgm2hdq27cqv3agm2awhz5se903vruatfhq77w3ls4evs3ch9zw97j25emudupq63nyw24cg27h2rspfj9srp",
+			"bc", 250_000_000, "1 cup coffee", nil, nil, time.Minute, new(big.Int)},
+		{"lnbc20m1pvjluezpp5qqqsyqcyq5rqwzqfqqqsyqcyq5rqwzqfqqqsyqcyq5rqwzqfqypqhp58yjmdan79s6qqdhdzgynm4zwqd5d7xmw5fk98klysy043l2ahrqscc6gd6ql3jrc5yzme8v4ntcewwz5cnw92tz0pc8qcuufvq7khhr8wpald05e92xw006sq94mg8v2ndf4sefvf9sygkshp5zfem29trqq2yxxz7",
+			"bc", 2_000_000_000, "", cake[:], nil, time.Hour, new(big.Int)},
+		{"lnbc1pvjluezsp5zyg3zyg3zyg3zyg3zyg3zyg3zyg3zyg3zyg3zyg3zyg3zyg3zygspp5qqqsyqcyq5rqwzqfqqqsyqcyq5rqwzqfqqqsyqcyq5rqwzqfqypqdpl2pkx2ctnv5sxxmmwwd5kgetjypeh2ursdae8g6twvus8g6rfwvs8qun0dfjkxaq9qrsgq357wnc5r2ueh7ck6q93dj32dlqnls087fxdwk8qakdyafkq3yap9us6v52vjjsrvywa6rt52cm9r9zqt8r2t7mlcwspyetp5h2tztugp9lfyql",
+			"bc", 0, "Please consider supporting this project", nil, secret, time.Hour, features},
+		{"lnbc2500u1pvjluezsp5zyg3zyg3zyg3zyg3zyg3zyg3zyg3zyg3zyg3zyg3zyg3zyg3zygspp5qqqsyqcyq5rqwzqfqqqsyqcyq5rqwzqfqqqsyqcyq5rqwzqfqypqdq5xysxxatsyp3k7enxv4jsxqzpu9qrsgquk0rl77nj30yxdy8j9vdx85fkpmdla2087ne0xh8nhedh8w27kyke0lp53ut353s06fv3qfegext0eh0ymjpf39tuven09sam30g4vgpfna3rh",
+			"bc", 250_000_000, "1 cup coffee", nil, secret, time.Minute, features},
+		{"lntb20m1pvjluezsp5zyg3zyg3zyg3zyg3zyg3zyg3zyg3zyg3zyg3zyg3zyg3zyg3zygshp58yjmdan79s6qqdhdzgynm4zwqd5d7xmw5fk98klysy043l2ahrqspp5qqqsyqcyq5rqwzqfqqqsyqcyq5rqwzqfqqqsyqcyq5rqwzqfqypqfpp3x9et2e20v6pu37c5d9vax37wxq72un989qrsgqdj545axuxtnfemtpwkc45hx9d2ft7x04mt8q7y6t0k2dge9e7h8kpy9p34ytyslj3yu569aalz2xdk8xkd7ltxqld94u8h2esmsmacgpghe9k8",
+			"tb", 2_000_000_000, "", cake[:], secret, time.Hour, features},
+	}
+	for _, test := range tests {
+		inv, err := base.ParseInvoice(test.s)
+		if err != nil {
+			t.Errorf("ParseInvoice(%s) got error: %v.", test.s, err)
+			continue
+		}
+		if inv.Currency != test.currency || inv.Amount != test.amount || inv.Timestamp.Unix() != 1496314658 {
+			t.Errorf("ParseInvoice(%s) got: %s %d %v expected: %s %d.", test.s, inv.Currency, inv.Amount, inv.Timestamp, test.currency, test.amount)
+		}
+		if !bytes.Equal(inv.PaymentHash(), hash) {
+			t.Errorf("PaymentHash of %s got: %x expected: %x.", test.s, inv.PaymentHash(), hash)
+		}
+		if d, ok := inv.Description(); d != test.description || ok != (test.description != "") {
+			t.Errorf("Description of %s got: %q expected: %q.", test.s, d, test.description)
+		}
+		if !bytes.Equal(inv.DescriptionHash(), test.descriptionHash) || !bytes.Equal(inv.PaymentSecret(), test.secret) {
+			t.Errorf("DescriptionHash and PaymentSecret of %s got: %x %x.", test.s, inv.DescriptionHash(), inv.PaymentSecret())
+		}
+		if inv.Expiry() != test.expiry || inv.MinFinalCLTVExpiry() != 18 || inv.Features().Cmp(test.features) != 0 {
+			t.Errorf("Expiry, MinFinalCLTVExpiry and Features of %s got: %v %d %v.", test.s, inv.Expiry(), inv.MinFinalCLTVExpiry(), inv.Features())
+		}
+
+		key, err := inv.Verify(secp256k1{})
+		if err != nil || hex.EncodeToString(key) != boltPubKey {
+			t.Errorf("Verify(%s) got: %x %v expected: %s.", test.s, key, err, boltPubKey)
+		}
+		res, err := inv.Encode()
+		if err != nil || res != test.s {
+			t.Errorf("Encode of %s got: %s %v.", test.s, res, err)
+		}
+	}
+
+	inv, _ := base.ParseInvoice(tests[5].s)
+	versions, programs := inv.Fallbacks()
+	if len(versions) != 1 || versions[0] != 17 || hex.EncodeToString(programs[0]) != "3172b5654f6683c8fb146959d347ce303cae4ca7" {
+		t.Errorf("Fallbacks got: %v %x.", versions, programs)
+	}
+}
+
+func TestInvoiceSign(t *testing.T) {
+	pub, _ := hex.DecodeString(boltPubKey)
+	hops := []base.RouteHop{
+		{PubKey: [33]byte{2, 1}, ShortChannelID: 0x0102030405060708, FeeBase: 1, FeeProportional: 20, CLTVExpiryDelta: 3},
+		{PubKey: [33]byte{3, 2}, ShortChannelID: 0x030405060708090a, FeeBase: 2, FeeProportional: 30, CLTVExpiryDelta: 4},
+	}
+	inv := &base.Invoice{
+		Currency:  "bcrt",
+		Amount:    967878534,
+		Timestamp: time.Unix(1572468703, 0).UTC(),
+		Fields: []base.InvoiceField{
+			base.BytesField(base.InvoicePaymentHash, bytes.Repeat([]byte{7}, 32)),
+			base.BytesField(base.InvoiceDescription, []byte("coffee beans")),
+			base.BytesField(base.InvoicePayee, pub),
+			base.IntField(base.InvoiceExpiry, 604800),
+			base.IntField(base.InvoiceMinFinalCLTVExpiry, 144),
+			base.RouteHintField(hops),
+			{Type: 31, Data: []byte{1, 2, 3}},
+		},
+	}
+	if err := inv.Sign(secp256k1{}); err != nil {
+		t.Fatalf("Sign got error: %v.", err)
+	}
+	s, err := inv.Encode()
+	if err != nil {
+		t.Fatalf("Encode got error: %v.", err)
+	}
+	// 967878534 msat is not a multiple of 100 and needs the pico multiplier.
+	if s[:17] != "lnbcrt9678785340p" {
+		t.Errorf("Encode got prefix: %s.", s[:17])
+	}
+
+	back, err := base.ParseInvoice(s)
+	if err != nil || !reflect.DeepEqual(back, inv) {
+		t.Fatalf("ParseInvoice(%s) got: %+v %v expected: %+v.", s, back, err, inv)
+	}
+	if key, err := back.Verify(secp256k1{}); err != nil || !bytes.Equal(key, pub) {
+		t.Errorf("Verify got: %x %v expected: %x.", key, err, pub)
+	}
+	if back.Expiry() != 7*24*time.Hour || back.MinFinalCLTVExpiry() != 144 {
+		t.Errorf("Expiry and MinFinalCLTVExpiry got: %v %d.", back.Expiry(), back.MinFinalCLTVExpiry())
+	}
+	if routes, err := back.RouteHints(); err != nil || len(routes) != 1 || !reflect.DeepEqual(routes[0], hops) {
+		t.Errorf("RouteHints got: %+v %v expected: %+v.", routes, err, hops)
+	}
+
+	// A signature by another node does not match the payee field.
+	back.Fields[2] = base.BytesField(base.InvoicePayee, append([]byte{2}, make([]byte, 32)...))
+	if _, err := back.Verify(secp256k1{}); err == nil {
+		t.Errorf("Verify accepted a signature not matching the payee.")
+	}
+}
+
+func TestInvoiceAmountAsWritten(t *testing.T) {
+	// The fields of the 1 cup coffee example of BOLT #11 signed under amounts that are not written with the largest multiplier.
+	valid := "lnbc2500u1pvjluezpp5qqqsyqcyq5rqwzqfqqqsyqcyq5rqwzqfqqqsyqcyq5rqwzqfqypqdq5xysxxatsyp3k7enxv4jsxqzpuaztrnwngzn3kdzw5hydlzf03qdgm2hdq27cqv3agm2awhz5se903vruatfhq77w3ls4evs3ch9zw97j25emudupq63nyw24cg27h2rspfj9srp"
+	_, d, _, _ := base.DecodeBech32(valid, 0)
+	d = d[:len(d)-104]
+	for _, hrp := range []string{"lnbc2500000n", "lnbc2500000000p", "lnbc2500u"} {
+		b, _ := base.ConvertBits(d, 5, 8, true)
+		sig, id, _ := secp256k1{}.SignCompact(sha256.Sum256(append([]byte(hrp), b...)))
+		s5, _ := base.ConvertBits(append(sig[:], id), 8, 5, true)
+		s, _ := base.EncodeBech32(hrp, append(d[:len(d):len(d)], s5...), base.Bech32)
+
+		inv, err := base.ParseInvoice(s)
+		if err != nil || inv.Amount != 250_000_000 {
+			t.Errorf("ParseInvoice(%s) got: %+v %v expected amount: 250000000.", s, inv, err)
+			continue
+		}
+		if key, err := inv.Verify(secp256k1{}); err != nil || hex.EncodeToString(key) != boltPubKey {
+			t.Errorf("Verify(%s) got: %x %v expected: %s.", s, key, err, boltPubKey)
+		}
+		if res, err := inv.Encode(); err != nil || res != s {
+			t.Errorf("Encode of %s got: %s %v.", s, res, err)
+		}
+		// A changed amount is written with the largest multiplier.
+		inv.Amount = 150_000_000
+		if res, err := inv.Encode(); err != nil || res[:9] != "lnbc1500u" {
+			t.Errorf("Encode of %s with a changed amount got: %s %v expected prefix: lnbc1500u.", s, res, err)
+		}
+	}
+}
+
+func TestParseInvoiceErrors(t *testing.T) {
+	valid := "lnbc2500u1pvjluezpp5qqqsyqcyq5rqwzqfqqqsyqcyq5rqwzqfqqqsyqcyq5rqwzqfqypqdq5xysxxatsyp3k7enxv4jsxqzpuaztrnwngzn3kdzw5hydlzf03qdgm2hdq27cqv3agm2awhz5se903vruatfhq77w3ls4evs3ch9zw97j25emudupq63nyw24cg27h2rspfj9srp"
+	inv, _ := base.ParseInvoice(valid)
+	data := func(hrp string) string {
+		// Reuse the fields and signature of the valid invoice under another human readable part.
+		_, d, _, _ := base.DecodeBech32(valid, 0)
+		s, _ := base.EncodeBech32(hrp, d, base.Bech32)
+		return s
+	}
+	short, _ := base.EncodeBech32("lnbc", make([]byte, 100), base.Bech32)
+	for _, s := range []string{
+		valid[:len(valid)-1] + "q", // wrong checksum
+		data("bc2500u"),            // missing ln prefix
+		data("ln2500u"),            // missing currency
+		data("lnbc02500u"),         // leading zero
+		data("lnbc2501p"),          // sub millisatoshi amount
+		data("lnbc1000000000000"),  // overflow
+		data("lnbc2500x"),          // unknown multiplier
+		short,                      // too short for a signature
+	} {
+		if _, err := base.ParseInvoice(s); err == nil {
+			t.Errorf("ParseInvoice(%s) succeeded, expected an error.", s)
+		}
+	}
+
+	inv.Fields = append(inv.Fields, base.InvoiceField{Type: 1, Data: make([]byte, 1024)})
+	if _, err := inv.Encode(); err == nil {
+		t.Errorf("Encode accepted a field longer than 1023 values.")
+	}
+}
+
+// secp256k1 implements base.InvoiceVerifier and base.InvoiceSigner with the BOLT #11 example key using slow textbook arithmetic.
+type secp256k1 struct{}
+
+var (
+	secpP, _  = new(big.Int).SetString("fffffffffffffffffffffffffffffffffffffffffffffffffffffffefffffc2f", 16)
+	secpN, _  = new(big.Int).SetString("fffffffffffffffffffffffffffffffebaaedce6af48a03bbfd25e8cd0364141", 16)
+	secpGx, _ = new(big.Int).SetString("79be667ef9dcbbac55a06295ce870b07029bfcdb2dce28d959f2815b16f81798", 16)
+	secpGy, _ = new(big.Int).SetString("483ada7726a3c4655da4fbfc0e1108a8fd17b448a68554199c47d08ffb10d4b8", 16)
+)
+
+// secpAdd returns the sum of two points, nil is the point at infinity.
+func secpAdd(p, q [2]*big.Int, pInf, qInf bool) ([2]*big.Int, bool) {
+	switch {
+	case pInf:
+		return q, qInf
+	case qInf:
+		return p, pInf
+	}
+	var l *big.Int
+	if p[0].Cmp(q[0]) == 0 {
+		if p[1].Cmp(q[1]) != 0 || p[1].Sign() == 0 {
+			return [2]*big.Int{}, true
+		}
+		// Tangent slope 3x²/2y
+		l = new(big.Int).Mul(p[0], p[0])
+		l.Mul(l, big.NewInt(3))
+		l.Mul(l, new(big.Int).ModInverse(new(big.Int).Lsh(p[1], 1), secpP))
+	} else {
+		dx := new(big.Int).Sub(q[0], p[0])
+		l = new(big.Int).Sub(q[1], p[1])
+		l.Mul(l, dx.ModInverse(dx.Mod(dx, secpP), secpP))
+	}
+	l.Mod(l, secpP)
+	x := new(big.Int).Mul(l, l)
+	x.Sub(x, p[0]).Sub(x, q[0]).Mod(x, secpP)
+	y := new(big.Int).Sub(p[0], x)
+	y.Mul(y, l).Sub(y, p[1]).Mod(y, secpP)
+	return [2]*big.Int{x, y}, false
+}
+
+func secpMul(k *big.Int, p [2]*big.Int) ([2]*big.Int, bool) {
+	var r [2]*big.Int
+	inf := true
+	for i := k.BitLen() - 1; i >= 0; i-- {
+		r, inf = secpAdd(r, r, inf, inf)
+		if k.Bit(i) == 1 {
+			r, inf = secpAdd(r, p, inf, false)
+		}
+	}
+	return r, inf
+}
+
+func secpCompress(p [2]*big.Int) []byte {
+	return append([]byte{byte(2 + p[1].Bit(0))}, p[0].FillBytes(make([]byte, 32))...)
+}
+
+func (secp256k1) RecoverPubKey(hash [32]byte, sig [64]byte, id byte) ([]byte, error) {
+	r := new(big.Int).SetBytes(sig[:32])
+	s := new(big.Int).SetBytes(sig[32:])
+	x := new(big.Int).Set(r)
+	if id&2 != 0 {
+		x.Add(x, secpN)
+	}
+	// y² = x³ + 7, p ≡ 3 mod 4 so the square root is a power.
+	y2 := new(big.Int).Exp(x, big.NewInt(3), secpP)
+	y2.Add(y2, big.NewInt(7)).Mod(y2, secpP)
+	y := new(big.Int).Exp(y2, new(big.Int).Rsh(new(big.Int).Add(secpP, big.NewInt(1)), 2), secpP)
+	if new(big.Int).Exp(y, big.NewInt(2), secpP).Cmp(y2) != 0 {
+		return nil, fmt.Errorf("invalid signature")
+	}
+	if y.Bit(0) != uint(id&1) {
+		y.Sub(secpP, y)
+	}
+	// Q = r⁻¹(sR - eG)
+	e := new(big.Int).SetBytes(hash[:])
+	sR, _ := secpMul(s, [2]*big.Int{x, y})
+	eG, _ := secpMul(new(big.Int).Sub(secpN, e.Mod(e, secpN)), [2]*big.Int{secpGx, secpGy})
+	sum, inf := secpAdd(sR, eG, false, false)
+	if inf {
+		return nil, fmt.Errorf("invalid signature")
+	}
+	q, _ := secpMul(new(big.Int).ModInverse(r, secpN), sum)
+	return secpCompress(q), nil
+}
+
+// SignCompact signs with a fixed nonce, which is only acceptable in tests.
+func (secp256k1) SignCompact(hash [32]byte) (sig [64]byte, id byte, err error) {
+	d, _ := new(big.Int).SetString(boltPrivKey, 16)
+	k := big.NewInt(0x5eed)
+	R, _ := secpMul(k, [2]*big.Int{secpGx, secpGy})
+	r := new(big.Int).Mod(R[0], secpN)
+	s := new(big.Int).Mul(r, d)
+	s.Add(s, new(big.Int).SetBytes(hash[:]))
+	s.Mul(s, new(big.Int).ModInverse(k, secpN)).Mod(s, secpN)
+	id = byte(R[1].Bit(0))
+	if R[0].Cmp(secpN) >= 0 {
+		id |= 2
+	}
+	r.FillBytes(sig[:32])
+	s.FillBytes(sig[32:])
+	return sig, id, nil
+}
+
+func TestInvoiceExpiry(t *testing.T) {
+	tests := []struct {
+		fields []base.InvoiceField
+		exp    time.Duration
+	}{
+		{nil, time.Hour},
+		{[]base.InvoiceField{base.IntField(base.InvoiceExpiry, 60)}, time.Minute},
+		{[]base.InvoiceField{base.IntField(base.InvoiceExpiry, 1<<33)}, (1 << 33) * time.Second},
+		// Up to 35 bits fit in the field, more seconds than a time.Duration can hold are capped.
+		{[]base.InvoiceField{base.IntField(base.InvoiceExpiry, 1<<34)}, math.MaxInt64},
+		{[]base.InvoiceField{base.IntField(base.InvoiceExpiry, 1<<35-1)}, math.MaxInt64},
+	}
+	for _, test := range tests {
+		inv := base.Invoice{Fields: test.fields}
+		if res := inv.Expiry(); res != test.exp {
+			t.Errorf("Expiry of %v got: %v expected: %v.", test.fields, res, test.exp)
+		}
+	}
+}
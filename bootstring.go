@@ -0,0 +1,229 @@
+// Use of this source code is governed by the CC0 1.0
+// license that can be found in the LICENSE file or here:
+// http://creativecommons.org/publicdomain/zero/1.0/
+
+package base
+
+import (
+	"fmt"
+	"math"
+	"strings"
+	"unicode/utf8"
+)
+
+// Bootstring holds the parameters of a Bootstring encoding as described in RFC 3492.
+//
+// Bootstring represents a string of Unicode code points using only basic code points, the ASCII code points below InitialN.
+// The basic code points are copied as is and every other code point is stored as a variable length integer using the digits of Digits.
+type Bootstring struct {
+	// Digits holds the digits of the variable length integers, the base of the encoding is Digits.Len().
+	Digits *Alphabet
+	// TMin, TMax, Skew and Damp control the thresholds and the bias adaptation of the variable length integers.
+	TMin, TMax  int
+	Skew, Damp  int
+	InitialBias int
+	// InitialN is the first non basic code point, at most 0x80.
+	InitialN rune
+	// Delimiter separates the basic code points from the variable length integers, it must be a basic code point that is not a digit.
+	Delimiter byte
+}
+
+// Punycode is the Bootstring instance for internationalized domain name labels defined in RFC 3492.
+var Punycode = &Bootstring{
+	Digits:      mustAlphabet("abcdefghijklmnopqrstuvwxyz0123456789").CaseInsensitive(),
+	TMin:        1,
+	TMax:        26,
+	Skew:        38,
+	Damp:        700,
+	InitialBias: 72,
+	InitialN:    0x80,
+	Delimiter:   '-',
+}
+
+// maxBootstring is the limit of all intermediate values, the same limit as 32 bit implementations of RFC 3492.
+const maxBootstring = math.MaxInt32
+
+// Encode returns the Bootstring encoding of the Unicode string s.
+//
+// Encode returns an error if s is not valid UTF-8 or if s is so long that the encoding would overflow.
+func (bs *Bootstring) Encode(s string) (string, error) {
+	if err := bs.check(); err != nil {
+		return "", err
+	}
+	if !utf8.ValidString(s) {
+		return "", fmt.Errorf("Illegal UTF-8 in Bootstring encoding.")
+	}
+	input := []rune(s)
+	base := bs.Digits.Len()
+
+	var out strings.Builder
+	for _, c := range input {
+		if c < bs.InitialN {
+			out.WriteRune(c)
+		}
+	}
+	b := out.Len()
+	h := b
+	if b > 0 {
+		out.WriteByte(bs.Delimiter)
+	}
+
+	n, delta, bias := int(bs.InitialN), 0, bs.InitialBias
+	for h < len(input) {
+		// The smallest code point not yet handled.
+		m := maxBootstring
+		for _, c := range input {
+			if int(c) >= n && int(c) < m {
+				m = int(c)
+			}
+		}
+		if m-n > (maxBootstring-delta)/(h+1) {
+			return "", fmt.Errorf("Bootstring encoding overflow.")
+		}
+		delta += (m - n) * (h + 1)
+		n = m
+
+		for _, c := range input {
+			if int(c) < n {
+				if delta == maxBootstring {
+					return "", fmt.Errorf("Bootstring encoding overflow.")
+				}
+				delta++
+			}
+			if int(c) != n {
+				continue
+			}
+			q := delta
+			for k := base; ; k += base {
+				t := bs.threshold(k, bias)
+				if q < t {
+					break
+				}
+				out.WriteByte(bs.Digits.chars[t+(q-t)%(base-t)])
+				q = (q - t) / (base - t)
+			}
+			out.WriteByte(bs.Digits.chars[q])
+			bias = bs.adapt(delta, h+1, h == b)
+			delta = 0
+			h++
+		}
+		delta++
+		n++
+	}
+	return out.String(), nil
+}
+
+// Decode returns the Unicode string represented by the Bootstring encoding s.
+//
+// Decode returns an error if s contains non basic code points before the last delimiter, illegal digits after it,
+// ends in the middle of an integer or if any value overflows.
+func (bs *Bootstring) Decode(s string) (string, error) {
+	if err := bs.check(); err != nil {
+		return "", err
+	}
+	base := bs.Digits.Len()
+
+	var output []rune
+	pos := 0
+	if b := strings.LastIndexByte(s, bs.Delimiter); b >= 0 {
+		for i := 0; i < b; i++ {
+			if rune(s[i]) >= bs.InitialN {
+				return "", fmt.Errorf("Illegal non basic code point in Bootstring decoding.")
+			}
+			output = append(output, rune(s[i]))
+		}
+		pos = b + 1
+	}
+
+	n, i, bias := int(bs.InitialN), 0, bs.InitialBias
+	for pos < len(s) {
+		oldi, w := i, 1
+		for k := base; ; k += base {
+			if pos >= len(s) {
+				return "", fmt.Errorf("Truncated Bootstring integer.")
+			}
+			digit := int(bs.Digits.dec[s[pos]])
+			pos++
+			if digit < 0 {
+				return "", fmt.Errorf("Illegal character %q in Bootstring decoding.", s[pos-1])
+			}
+			if digit > (maxBootstring-i)/w {
+				return "", fmt.Errorf("Bootstring decoding overflow.")
+			}
+			i += digit * w
+			t := bs.threshold(k, bias)
+			if digit < t {
+				break
+			}
+			if w > maxBootstring/(base-t) {
+				return "", fmt.Errorf("Bootstring decoding overflow.")
+			}
+			w *= base - t
+		}
+		l := len(output) + 1
+		bias = bs.adapt(i-oldi, l, oldi == 0)
+		if i/l > maxBootstring-n {
+			return "", fmt.Errorf("Bootstring decoding overflow.")
+		}
+		n += i / l
+		i %= l
+		if n < int(bs.InitialN) || n > utf8.MaxRune || (n >= 0xD800 && n <= 0xDFFF) {
+			return "", fmt.Errorf("Illegal code point %#x in Bootstring decoding.", n)
+		}
+		output = append(output, 0)
+		copy(output[i+1:], output[i:])
+		output[i] = rune(n)
+		i++
+	}
+	return string(output), nil
+}
+
+func (bs *Bootstring) threshold(k, bias int) int {
+	switch {
+	case k <= bias+bs.TMin:
+		return bs.TMin
+	case k >= bias+bs.TMax:
+		return bs.TMax
+	}
+	return k - bias
+}
+
+func (bs *Bootstring) adapt(delta, points int, first bool) int {
+	base := bs.Digits.Len()
+	if first {
+		delta /= bs.Damp
+	} else {
+		delta /= 2
+	}
+	delta += delta / points
+	k := 0
+	for delta > (base-bs.TMin)*bs.TMax/2 {
+		delta /= base - bs.TMin
+		k += base
+	}
+	return k + (base-bs.TMin+1)*delta/(delta+bs.Skew)
+}
+
+// check validates the parameter constraints of RFC 3492 section 5.
+func (bs *Bootstring) check() error {
+	if bs.Digits == nil {
+		return fmt.Errorf("Illegal Bootstring without digits.")
+	}
+	base := bs.Digits.Len()
+	switch {
+	case bs.TMin < 0 || bs.TMin > bs.TMax || bs.TMax >= base:
+		return fmt.Errorf("Illegal Bootstring thresholds %d and %d.", bs.TMin, bs.TMax)
+	case bs.Skew < 1 || bs.Damp < 2:
+		return fmt.Errorf("Illegal Bootstring skew %d or damp %d.", bs.Skew, bs.Damp)
+	case bs.InitialBias < 0 || bs.InitialBias%base > base-bs.TMin:
+		return fmt.Errorf("Illegal Bootstring initial bias %d.", bs.InitialBias)
+	case bs.InitialN < 1 || bs.InitialN > 0x80 || rune(bs.Delimiter) >= bs.InitialN || bs.Digits.dec[bs.Delimiter] >= 0:
+		return fmt.Errorf("Illegal Bootstring delimiter %q.", bs.Delimiter)
+	}
+	for i := 0; i < base; i++ {
+		if rune(bs.Digits.chars[i]) >= bs.InitialN {
+			return fmt.Errorf("Illegal non basic Bootstring digit %q.", bs.Digits.chars[i])
+		}
+	}
+	return nil
+}
@@ -0,0 +1,115 @@
+package base_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/7i/base"
+)
+
+// Sample strings from RFC 3492 section 7.1
+var punycodeTests = []struct {
+	decoded, encoded string
+}{
+	{"ليهمابتكلموشعربي؟", "egbpdaj6bu4bxfgehfvwxn"},
+	{"他们为什么不说中文", "ihqwcrb4cv8a8dqg056pqjye"},
+	{"他們爲什麽不說中文", "ihqwctvzc91f659drss3x8bo0yb"},
+	{"Pročprostěnemluvíčesky", "Proprostnemluvesky-uyb24dma41a"},
+	{"למההםפשוטלאמדבריםעברית", "4dbcagdahymbxekheh6e0a7fei0b"},
+	{"यहलोगहिन्दीक्योंनहींबोलसकतेहैं", "i1baa7eci9glrd9b2ae1bj0hfcgg6iyaf8o0a1dig0cd"},
+	{"なぜみんな日本語を話してくれないのか", "n8jok5ay5dzabd5bym9f0cm5685rrjetr6pdxa"},
+	{"세계의모든사람들이한국어를이해한다면얼마나좋을까", "989aomsvi5e83db1d2a355cv1e0vak1dwrv93d5xbh15a0dt30a5jpsd879ccm6fea98c"},
+	{"почемужеонинеговорятпорусски", "b1abfaaepdrnnbgefbadotcwatmq2g4l"},
+	{"PorquénopuedensimplementehablarenEspañol", "PorqunopuedensimplementehablarenEspaol-fmd56a"},
+	{"TạisaohọkhôngthểchỉnóitiếngViệt", "TisaohkhngthchnitingVit-kjcr8268qyxafd2f1b9g"},
+	{"3年B組金八先生", "3B-ww4c5e180e575a65lsy2b"},
+	{"安室奈美恵-with-SUPER-MONKEYS", "-with-SUPER-MONKEYS-pc58ag80a8qai00g7n9n"},
+	{"Hello-Another-Way-それぞれの場所", "Hello-Another-Way--fc4qua05auwb3674vfr0b"},
+	{"ひとつ屋根の下2", "2-u9tlzr9756bt3uc0v"},
+	{"MajiでKoiする5秒前", "MajiKoi5-783gue6qz075azm5e"},
+	{"パフィーdeルンバ", "de-jg4avhby1noc0d"},
+	{"そのスピードで", "d9juau41awczczp"},
+	{"-> $1.00 <-", "-> $1.00 <--"},
+	{"", ""},
+	{"abc", "abc-"},
+	{"ü", "tda"},
+}
+
+func TestPunycode(t *testing.T) {
+	for _, test := range punycodeTests {
+		res, err := base.Punycode.Encode(test.decoded)
+		if err != nil || res != test.encoded {
+			t.Errorf("Punycode.Encode(%q) got: %q %v expected: %q.", test.decoded, res, err, test.encoded)
+		}
+		res, err = base.Punycode.Decode(test.encoded)
+		if err != nil || res != test.decoded {
+			t.Errorf("Punycode.Decode(%q) got: %q %v expected: %q.", test.encoded, res, err, test.decoded)
+		}
+	}
+	// Digits are case insensitive
+	res, err := base.Punycode.Decode("b1abfaaepdrnnbgefbaDotcwatmq2g4l")
+	if err != nil || res != "почемужеонинеговорятпорусски" {
+		t.Errorf("Punycode.Decode did not accept upper case digits, got: %q %v.", res, err)
+	}
+}
+
+func TestPunycodeErrors(t *testing.T) {
+	for _, s := range []string{
+		"ü-tda",                 // non basic code point before the delimiter
+		"abc-ü",                 // illegal digit
+		"abc-99",                // truncated integer
+		"zzzzzzzz",              // code point out of range
+		"99999999999999999999a", // integer overflow
+		"de-" + strings.Repeat("9", 20),
+	} {
+		if res, err := base.Punycode.Decode(s); err == nil {
+			t.Errorf("Punycode.Decode(%q) succeeded with %q, expected an error.", s, res)
+		}
+	}
+	if _, err := base.Punycode.Encode("\xff"); err == nil {
+		t.Errorf("Punycode.Encode accepted invalid UTF-8.")
+	}
+	// Enough code points to overflow delta while encoding
+	long := strings.Repeat("a", 50000) + strings.Repeat(string(rune(0x10FFFF)), 2)
+	if _, err := base.Punycode.Encode(long); err == nil {
+		t.Errorf("Punycode.Encode did not detect overflow.")
+	}
+}
+
+func TestBootstringParameters(t *testing.T) {
+	// A Bootstring using base 10 digits with a delimiter outside the digits
+	bs := &base.Bootstring{
+		TMin:        1,
+		TMax:        8,
+		Skew:        38,
+		Damp:        700,
+		InitialBias: 9,
+		InitialN:    0x80,
+		Delimiter:   '_',
+	}
+	bs.Digits, _ = base.Digits(10)
+	for _, s := range []string{"", "plain", "Grüße_aus_Köln", "日本語"} {
+		e, err := bs.Encode(s)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if strings.Trim(e[strings.LastIndexByte(e, '_')+1:], "0123456789") != "" {
+			t.Errorf("Bootstring.Encode(%q) got: %q with non digit characters.", s, e)
+		}
+		d, err := bs.Decode(e)
+		if err != nil || d != s {
+			t.Errorf("Bootstring.Decode(%q) got: %q %v expected: %q.", e, d, err, s)
+		}
+	}
+
+	bad := *bs
+	bad.Delimiter = '5'
+	if _, err := bad.Encode("ü"); err == nil {
+		t.Errorf("Bootstring accepted a delimiter that is a digit.")
+	}
+	bad = *bs
+	bad.TMax = 10
+	if _, err := bad.Decode("1"); err == nil {
+		t.Errorf("Bootstring accepted TMax equal to the base.")
+	}
+}
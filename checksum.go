@@ -0,0 +1,120 @@
+// Use of this source code is governed by the CC0 1.0
+// license that can be found in the LICENSE file or here:
+// http://creativecommons.org/publicdomain/zero/1.0/
+
+package base
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"fmt"
+	"math/big"
+	"time"
+)
+
+// CrockfordCheck is Crockford's base32 with its optional check symbol, registered as "crockfordcheck".
+//
+// Encode appends the value of the data modulo 37 as a check symbol, one of the 32 digits of Crockford or *~$=U for 32 to 36.
+// Decode accepts everything Crockford accepts, ignores hyphens between characters and returns a *CheckDigitError for a wrong check symbol.
+// Like Crockford it is a positional encoding, zero is encoded as a single 0 digit followed by its check symbol.
+var CrockfordCheck Encoding = crockfordCheck{}
+
+// crockfordCheckSymbols holds the check symbols for the values 32 to 36.
+const crockfordCheckSymbols = "*~$=U"
+
+type crockfordCheck struct{}
+
+func (c crockfordCheck) Encode(src []byte) (r []byte) {
+	if o := loadObserver(); o != nil {
+		defer observe(o, OpEncode, eventName(c), len(src), time.Now(), &r, nil)
+	}
+	return c.encode(src)
+}
+
+func (c crockfordCheck) Decode(src []byte) (r []byte, err error) {
+	if o := loadObserver(); o != nil {
+		defer observe(o, OpDecode, eventName(c), len(src), time.Now(), &r, &err)
+	}
+	return c.decode(src)
+}
+
+func (crockfordCheck) encode(src []byte) []byte {
+	n := new(big.Int).SetBytes(src)
+	r, _ := Crockford.EncodeInt(n)
+	return append(r, crockfordCheckSymbol(n))
+}
+
+func (crockfordCheck) decode(src []byte) ([]byte, error) {
+	src = bytes.ReplaceAll(src, []byte("-"), nil)
+	if len(src) < 2 {
+		return nil, fmt.Errorf("Illegal Crockford check length %d.", len(src))
+	}
+	n, err := Crockford.DecodeInt(src[:len(src)-1])
+	if err != nil {
+		return nil, err
+	}
+	c := src[len(src)-1]
+	if c == 'u' {
+		c = 'U'
+	}
+	exp := crockfordCheckSymbol(n)
+	if v := Crockford.dec[c]; c != exp && (v < 0 || Crockford.chars[v] != exp) {
+		return nil, &CheckDigitError{Kind: "Crockford", Got: string(src[len(src)-1]), Expected: string(exp)}
+	}
+	return n.Bytes(), nil
+}
+
+func crockfordCheckSymbol(n *big.Int) byte {
+	m := new(big.Int).Mod(n, big.NewInt(37)).Int64()
+	if m < 32 {
+		return Crockford.chars[m]
+	}
+	return crockfordCheckSymbols[m-32]
+}
+
+// Base58Check is the bitcoin Base58Check encoding, registered as "base58check".
+//
+// Encode appends the first four bytes of the double SHA-256 of src, usually a version byte and a payload, and encodes the result like Base58BTC.
+// Decode verifies and removes the checksum, a wrong checksum is reported as a *CheckDigitError.
+var Base58Check Encoding = base58Check{}
+
+type base58Check struct{}
+
+func (c base58Check) Encode(src []byte) (r []byte) {
+	if o := loadObserver(); o != nil {
+		defer observe(o, OpEncode, eventName(c), len(src), time.Now(), &r, nil)
+	}
+	return c.encode(src)
+}
+
+func (c base58Check) Decode(src []byte) (r []byte, err error) {
+	if o := loadObserver(); o != nil {
+		defer observe(o, OpDecode, eventName(c), len(src), time.Now(), &r, &err)
+	}
+	return c.decode(src)
+}
+
+func (base58Check) encode(src []byte) []byte {
+	sum := doubleSHA256(src)
+	return Base58BTC.(leadingZeros).encode(append(append([]byte(nil), src...), sum[:4]...))
+}
+
+func (base58Check) decode(src []byte) ([]byte, error) {
+	b, err := Base58BTC.(leadingZeros).decode(src)
+	if err != nil {
+		return nil, err
+	}
+	if len(b) < 4 {
+		return nil, fmt.Errorf("Illegal Base58Check length %d.", len(b))
+	}
+	data, check := b[:len(b)-4], b[len(b)-4:]
+	if sum := doubleSHA256(data); !bytes.Equal(sum[:4], check) {
+		return nil, &CheckDigitError{Kind: "Base58Check", Got: fmt.Sprintf("%x", check), Expected: fmt.Sprintf("%x", sum[:4])}
+	}
+	return data, nil
+}
+
+func doubleSHA256(b []byte) [32]byte {
+	h := sha256.Sum256(b)
+	return sha256.Sum256(h[:])
+}
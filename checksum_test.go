@@ -0,0 +1,81 @@
+package base_test
+
+import (
+	"bytes"
+	"encoding/hex"
+	"errors"
+	"math/big"
+	"testing"
+
+	"github.com/7i/base"
+)
+
+func TestCrockfordCheck(t *testing.T) {
+	tests := []struct {
+		n   uint64
+		exp string
+	}{
+		{0, "00"},
+		{1, "11"},
+		{1234, "16JD"},
+		{32, "10*"},
+		{36, "14U"},
+		{1<<64 - 1, "FZZZZZZZZZZZZB"},
+	}
+	for _, test := range tests {
+		data := new(big.Int).SetUint64(test.n).Bytes()
+		res := base.CrockfordCheck.Encode(data)
+		if string(res) != test.exp {
+			t.Errorf("CrockfordCheck.Encode(%d) got: %s expected: %s.", test.n, res, test.exp)
+		}
+		dec, err := base.CrockfordCheck.Decode([]byte(test.exp))
+		if err != nil || !bytes.Equal(dec, data) {
+			t.Errorf("CrockfordCheck.Decode(%s) got: %x %v expected: %x.", test.exp, dec, err, data)
+		}
+	}
+
+	// Hyphens, lower case, aliases and a lower case u check symbol are accepted.
+	for _, s := range []string{"16-jd", "1-6-J-D", "i6jd", "l4u"} {
+		if _, err := base.CrockfordCheck.Decode([]byte(s)); err != nil {
+			t.Errorf("CrockfordCheck.Decode(%s) got error: %v.", s, err)
+		}
+	}
+
+	_, err := base.CrockfordCheck.Decode([]byte("16JE"))
+	var cde *base.CheckDigitError
+	if !errors.As(err, &cde) || cde.Got != "E" || cde.Expected != "D" {
+		t.Errorf("CrockfordCheck.Decode(16JE) got: %v.", err)
+	}
+	for _, s := range []string{"", "1", "U6JD", "1*JD"} {
+		if _, err := base.CrockfordCheck.Decode([]byte(s)); err == nil {
+			t.Errorf("CrockfordCheck.Decode(%q) succeeded, expected an error.", s)
+		}
+	}
+}
+
+func TestBase58Check(t *testing.T) {
+	// The example address of the bitcoin wiki
+	data, _ := hex.DecodeString("00010966776006953d5567439e5e39f86a0d273bee")
+	const exp = "16UwLL9Risc3QfPqBUvKofHmBQ7wMtjvM"
+	if res := base.Base58Check.Encode(data); string(res) != exp {
+		t.Errorf("Base58Check.Encode(%x) got: %s expected: %s.", data, res, exp)
+	}
+	dec, err := base.Base58Check.Decode([]byte(exp))
+	if err != nil || !bytes.Equal(dec, data) {
+		t.Errorf("Base58Check.Decode(%s) got: %x %v expected: %x.", exp, dec, err, data)
+	}
+
+	_, err = base.Base58Check.Decode([]byte("16UwLL9Risc3QfPqBUvKofHmBQ7wMtjvN"))
+	var cde *base.CheckDigitError
+	if !errors.As(err, &cde) || cde.Kind != "Base58Check" {
+		t.Errorf("Base58Check.Decode of a changed address got: %v.", err)
+	}
+	for _, s := range []string{"", "1", "0OIl"} {
+		if _, err := base.Base58Check.Decode([]byte(s)); err == nil {
+			t.Errorf("Base58Check.Decode(%q) succeeded, expected an error.", s)
+		}
+	}
+	if e, err := base.Lookup("base58check"); err != nil || e != base.Base58Check {
+		t.Errorf("Lookup(base58check) got: %v %v.", e, err)
+	}
+}
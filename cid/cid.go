@@ -0,0 +1,177 @@
+// Use of this source code is governed by the CC0 1.0
+// license that can be found in the LICENSE file or here:
+// http://creativecommons.org/publicdomain/zero/1.0/
+
+// Package cid parses and formats IPFS content identifiers.
+//
+// A version 0 CID is a bare base58btc encoded SHA2-256 multihash like QmdfTbBqBPQ7VNxZEYEj14VmRuZBkqFbiwReogJgS1zR1n.
+// A version 1 CID is a multibase string of the varint version, the varint multicodec of the content and its multihash,
+// like bafybeihdwdcefgh4dqkjv67uzcmw7ojee6xedzdetojuzjevtenxquvyku.
+package cid
+
+import (
+	"bytes"
+	"encoding/binary"
+	"fmt"
+
+	"github.com/7i/base"
+)
+
+// Multicodec codes of common content types and hash functions.
+const (
+	Raw      = 0x55
+	DagPB    = 0x70
+	DagCBOR  = 0x71
+	DagJSON  = 0x0129
+	SHA2_256 = 0x12
+)
+
+// maxVarintLength is the length limit of unsigned varints in multiformats, they hold at most 63 bits.
+const maxVarintLength = 9
+
+// CID is a content identifier.
+type CID struct {
+	// Version is 0 or 1.
+	Version uint64
+	// Codec is the multicodec of the content, always DagPB for version 0.
+	Codec uint64
+	// Multihash is the self describing hash of the content, a varint hash function, a varint digest length and the digest.
+	Multihash []byte
+}
+
+// NewV0 returns the version 0 CID of the SHA2-256 digest of DagPB content.
+func NewV0(digest []byte) (CID, error) {
+	if len(digest) != 32 {
+		return CID{}, fmt.Errorf("Illegal SHA2-256 digest length %d.", len(digest))
+	}
+	return CID{Version: 0, Codec: DagPB, Multihash: append([]byte{SHA2_256, 32}, digest...)}, nil
+}
+
+// NewV1 returns the version 1 CID of content with the multicodec codec and the multihash multihash.
+func NewV1(codec uint64, multihash []byte) (CID, error) {
+	if err := checkMultihash(multihash); err != nil {
+		return CID{}, err
+	}
+	return CID{Version: 1, Codec: codec, Multihash: append([]byte(nil), multihash...)}, nil
+}
+
+// Parse returns the CID in the string s, a version 0 CID or a multibase version 1 CID in any multibase encoding of package base.
+func Parse(s string) (CID, error) {
+	if len(s) == 46 && s[:2] == "Qm" {
+		b, err := base.Base58BTC.Decode([]byte(s))
+		if err != nil {
+			return CID{}, err
+		}
+		return Decode(b)
+	}
+	_, b, err := base.MultibaseDecode(s)
+	if err != nil {
+		return CID{}, err
+	}
+	c, err := Decode(b)
+	if err != nil {
+		return CID{}, err
+	}
+	if c.Version == 0 {
+		return CID{}, fmt.Errorf("Illegal multibase encoded version 0 CID.")
+	}
+	return c, nil
+}
+
+// Decode returns the CID in its binary form b, the 34 byte multihash of version 0 or the version, codec and multihash of version 1.
+func Decode(b []byte) (CID, error) {
+	if len(b) == 34 && b[0] == SHA2_256 && b[1] == 32 {
+		return CID{Version: 0, Codec: DagPB, Multihash: append([]byte(nil), b...)}, nil
+	}
+	v, n, err := readUvarint(b)
+	if err != nil {
+		return CID{}, err
+	}
+	if v != 1 {
+		return CID{}, fmt.Errorf("Unsupported CID version %d.", v)
+	}
+	b = b[n:]
+	codec, n, err := readUvarint(b)
+	if err != nil {
+		return CID{}, err
+	}
+	return NewV1(codec, b[n:])
+}
+
+// Bytes returns the binary form of c.
+func (c CID) Bytes() []byte {
+	if c.Version == 0 {
+		return append([]byte(nil), c.Multihash...)
+	}
+	b := binary.AppendUvarint(nil, c.Version)
+	b = binary.AppendUvarint(b, c.Codec)
+	return append(b, c.Multihash...)
+}
+
+// String returns c in its canonical form, base58btc without prefix for version 0 and multibase base32 for version 1.
+func (c CID) String() string {
+	if c.Version == 0 {
+		return string(base.Base58BTC.Encode(c.Multihash))
+	}
+	s, _ := c.Encode('b')
+	return s
+}
+
+// Encode returns the version 1 CID c in the multibase encoding identified by prefix, see base.MultibaseEncoding.
+// Version 0 CIDs have no multibase form, see V1.
+func (c CID) Encode(prefix byte) (string, error) {
+	if c.Version == 0 {
+		return "", fmt.Errorf("Illegal multibase encoding of version 0 CID.")
+	}
+	return base.MultibaseEncode(prefix, c.Bytes())
+}
+
+// V0 returns c as a version 0 CID. Only DagPB content with a SHA2-256 multihash has a version 0 CID.
+func (c CID) V0() (CID, error) {
+	if c.Codec != DagPB || len(c.Multihash) != 34 || c.Multihash[0] != SHA2_256 || c.Multihash[1] != 32 {
+		return CID{}, fmt.Errorf("CID has no version 0 form.")
+	}
+	c.Version = 0
+	return c, nil
+}
+
+// V1 returns c as a version 1 CID.
+func (c CID) V1() CID {
+	c.Version = 1
+	return c
+}
+
+// Equal reports whether c and d identify the same content in the same version.
+func (c CID) Equal(d CID) bool {
+	return c.Version == d.Version && c.Codec == d.Codec && bytes.Equal(c.Multihash, d.Multihash)
+}
+
+// checkMultihash returns an error unless mh is a hash function code, a digest length and a digest of that length.
+func checkMultihash(mh []byte) error {
+	_, n, err := readUvarint(mh)
+	if err != nil {
+		return err
+	}
+	l, m, err := readUvarint(mh[n:])
+	if err != nil {
+		return err
+	}
+	if uint64(len(mh)-n-m) != l {
+		return fmt.Errorf("Illegal multihash digest length %d, expected %d.", len(mh)-n-m, l)
+	}
+	return nil
+}
+
+// readUvarint returns the minimally encoded unsigned varint at the start of b and its length.
+func readUvarint(b []byte) (uint64, int, error) {
+	v, n := binary.Uvarint(b)
+	switch {
+	case n == 0:
+		return 0, 0, fmt.Errorf("Truncated varint.")
+	case n < 0 || n > maxVarintLength:
+		return 0, 0, fmt.Errorf("Varint overflows.")
+	case n > 1 && b[n-1] == 0:
+		return 0, 0, fmt.Errorf("Illegal non minimal varint.")
+	}
+	return v, n, nil
+}
@@ -0,0 +1,88 @@
+package cid_test
+
+import (
+	"bytes"
+	"encoding/hex"
+	"testing"
+
+	"github.com/7i/base/cid"
+)
+
+func TestParse(t *testing.T) {
+	// The example of the CID specification, the DagPB node with the SHA2-256 hash of the empty string.
+	const (
+		v0 = "QmdfTbBqBPQ7VNxZEYEj14VmRuZBkqFbiwReogJgS1zR1n"
+		v1 = "bafybeihdwdcefgh4dqkjv67uzcmw7ojee6xedzdetojuzjevtenxquvyku"
+	)
+	mh, _ := hex.DecodeString("1220e3b0c44298fc1c149afbf4c8996fb92427ae41e4649b934ca495991b7852b855")
+
+	c0, err := cid.Parse(v0)
+	if err != nil || c0.Version != 0 || c0.Codec != cid.DagPB || !bytes.Equal(c0.Multihash, mh) {
+		t.Fatalf("Parse(%s) got: %+v %v.", v0, c0, err)
+	}
+	if c0.String() != v0 {
+		t.Errorf("String of %s got: %s.", v0, c0.String())
+	}
+	c1 := c0.V1()
+	if c1.String() != v1 {
+		t.Errorf("V1 of %s got: %s expected: %s.", v0, c1.String(), v1)
+	}
+	p1, err := cid.Parse(v1)
+	if err != nil || !p1.Equal(c1) {
+		t.Errorf("Parse(%s) got: %+v %v expected: %+v.", v1, p1, err, c1)
+	}
+	back, err := p1.V0()
+	if err != nil || !back.Equal(c0) {
+		t.Errorf("V0 of %s got: %+v %v expected: %+v.", v1, back, err, c0)
+	}
+
+	// Other multibase encodings of the same CID
+	tests := []struct {
+		prefix byte
+		exp    string
+	}{
+		{'z', "zdj7Wkkhxcu2rsiN6GUyHCLsSLL47kdUNfjbFqBUUhMFTZKBi"},
+		{'k', "k2jmtxx1epa2wl096hsbpuhrz9xhppklonehzwkmskc9rmeb51kwn4ut"},
+		{'f', "f01701220e3b0c44298fc1c149afbf4c8996fb92427ae41e4649b934ca495991b7852b855"},
+	}
+	for _, test := range tests {
+		res, err := c1.Encode(test.prefix)
+		if err != nil || res != test.exp {
+			t.Errorf("Encode(%q) got: %s %v expected: %s.", test.prefix, res, err, test.exp)
+		}
+		p, err := cid.Parse(test.exp)
+		if err != nil || !p.Equal(c1) {
+			t.Errorf("Parse(%s) got: %+v %v expected: %+v.", test.exp, p, err, c1)
+		}
+	}
+
+	raw, err := cid.NewV1(cid.Raw, mh)
+	if err != nil || raw.String() != "bafkreihdwdcefgh4dqkjv67uzcmw7ojee6xedzdetojuzjevtenxquvyku" {
+		t.Errorf("NewV1(Raw) got: %s %v.", raw, err)
+	}
+	if _, err := raw.V0(); err == nil {
+		t.Errorf("V0 of a Raw CID succeeded, expected an error.")
+	}
+	if _, err := c0.Encode('b'); err == nil {
+		t.Errorf("Encode of a version 0 CID succeeded, expected an error.")
+	}
+	if d, err := cid.NewV0(mh[2:]); err != nil || !d.Equal(c0) {
+		t.Errorf("NewV0 got: %+v %v expected: %+v.", d, err, c0)
+	}
+}
+
+func TestParseErrors(t *testing.T) {
+	for _, s := range []string{
+		"",
+		"QmdfTbBqBPQ7VNxZEYEj14VmRuZBkqFbiwReogJgS1zR1",                               // too short for version 0 and not a multibase prefix
+		"bafybeihdwdcefgh4dqkjv67uzcmw7ojee6xedzdetojuzjevtenxquvyk",                  // truncated digest
+		"f02701220e3b0c44298fc1c149afbf4c8996fb92427ae41e4649b934ca495991b7852b855",   // version 2
+		"f0180001220e3b0c44298fc1c149afbf4c8996fb92427ae41e4649b934ca495991b7852b855", // non minimal varint codec
+		"zQmdfTbBqBPQ7VNxZEYEj14VmRuZBkqFbiwReogJgS1zR1n",                             // multibase version 0
+		"f0170122100", // digest shorter than its length
+	} {
+		if c, err := cid.Parse(s); err == nil {
+			t.Errorf("Parse(%q) got: %+v, expected an error.", s, c)
+		}
+	}
+}
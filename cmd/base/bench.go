@@ -0,0 +1,176 @@
+// Use of this source code is governed by the CC0 1.0
+// license that can be found in the LICENSE file or here:
+// http://creativecommons.org/publicdomain/zero/1.0/
+
+package main
+
+import (
+	"encoding/csv"
+	"flag"
+	"fmt"
+	"io"
+	"math/rand/v2"
+	"strconv"
+	"strings"
+	"testing"
+	"text/tabwriter"
+
+	"github.com/7i/base"
+)
+
+// benchResult is the measurement of one operation of one encoding at one input size.
+type benchResult struct {
+	encoding string
+	size     int
+	op       string
+	encLen   int
+	r        testing.BenchmarkResult
+}
+
+func (b benchResult) record() []string {
+	mbs := 0.0
+	if s := b.r.T.Seconds(); s > 0 {
+		mbs = float64(b.size) * float64(b.r.N) / s / 1e6
+	}
+	return []string{
+		b.encoding,
+		strconv.Itoa(b.size),
+		b.op,
+		strconv.FormatInt(b.r.NsPerOp(), 10),
+		strconv.FormatFloat(mbs, 'f', 2, 64),
+		strconv.FormatInt(b.r.AllocsPerOp(), 10),
+		strconv.FormatInt(b.r.AllocedBytesPerOp(), 10),
+		strconv.Itoa(b.encLen),
+		strconv.FormatFloat(float64(b.encLen)/float64(b.size), 'f', 3, 64),
+	}
+}
+
+var benchHeader = []string{"encoding", "size", "op", "ns_per_op", "mb_per_s", "allocs_per_op", "bytes_per_op", "encoded_len", "overhead"}
+
+// bench runs the bench command.
+func bench(args []string, stdout, stderr io.Writer) int {
+	fl := flag.NewFlagSet("bench", flag.ContinueOnError)
+	fl.SetOutput(stderr)
+	encodings := fl.String("encodings", "", "comma separated `names` of the encodings to measure, all of them if empty")
+	sizes := fl.String("sizes", "16,256,4096", "comma separated input `sizes` in bytes")
+	csvOut := fl.Bool("csv", false, "print CSV with a header line")
+	fl.Usage = func() {
+		fmt.Fprintf(stderr, "usage: base bench [flags]\n")
+		fl.PrintDefaults()
+	}
+	if err := fl.Parse(args); err != nil {
+		return 2
+	}
+	fail := func(err error) int {
+		fmt.Fprintf(stderr, "base bench: %v\n", err)
+		return 2
+	}
+
+	names := base.Names()
+	if *encodings != "" {
+		names = strings.Split(*encodings, ",")
+	} else {
+		for b := 62; b >= 2; b-- {
+			names = append([]string{strconv.Itoa(b)}, names...)
+		}
+	}
+	encs := make([]base.Encoding, len(names))
+	for i, name := range names {
+		enc, err := base.Lookup(name)
+		if err != nil {
+			return fail(err)
+		}
+		encs[i] = enc
+	}
+	var ns []int
+	for _, s := range strings.Split(*sizes, ",") {
+		n, err := strconv.Atoi(s)
+		if err != nil || n <= 0 {
+			return fail(fmt.Errorf("illegal size %q", s))
+		}
+		ns = append(ns, n)
+	}
+	var w interface {
+		Write(record []string) error
+	}
+	var flush func() error
+	if *csvOut {
+		cw := csv.NewWriter(stdout)
+		w, flush = cw, func() error {
+			cw.Flush()
+			return cw.Error()
+		}
+	} else {
+		tw := tabwriter.NewWriter(stdout, 0, 8, 2, ' ', tabwriter.AlignRight)
+		w, flush = tabWriter{tw}, tw.Flush
+	}
+	if err := w.Write(benchHeader); err != nil {
+		return fail(err)
+	}
+	rnd := rand.New(rand.NewPCG(1, 2))
+	for i, enc := range encs {
+		for _, n := range ns {
+			data := make([]byte, n)
+			for j := range data {
+				data[j] = byte(rnd.Uint32())
+			}
+			data[0] |= 1
+			encoded := enc.Encode(data)
+			results, err := benchEncoding(names[i], enc, data, encoded)
+			if err != nil {
+				flush()
+				return fail(err)
+			}
+			for _, res := range results {
+				if err := w.Write(res.record()); err != nil {
+					return fail(err)
+				}
+			}
+			if *csvOut {
+				// CSV rows are written as they are measured, the table is aligned once complete.
+				if err := flush(); err != nil {
+					return fail(err)
+				}
+			}
+		}
+	}
+	if err := flush(); err != nil {
+		return fail(err)
+	}
+	return 0
+}
+
+// benchEncoding measures Encode and Decode of enc with data and its encoding with testing.Benchmark.
+func benchEncoding(name string, enc base.Encoding, data, encoded []byte) ([]benchResult, error) {
+	if _, err := enc.Decode(encoded); err != nil {
+		return nil, fmt.Errorf("%s: %v", name, err)
+	}
+	encode := testing.Benchmark(func(b *testing.B) {
+		b.ReportAllocs()
+		b.SetBytes(int64(len(data)))
+		for b.Loop() {
+			enc.Encode(data)
+		}
+	})
+	decode := testing.Benchmark(func(b *testing.B) {
+		b.ReportAllocs()
+		b.SetBytes(int64(len(data)))
+		for b.Loop() {
+			enc.Decode(encoded)
+		}
+	})
+	return []benchResult{
+		{name, len(data), "encode", len(encoded), encode},
+		{name, len(data), "decode", len(encoded), decode},
+	}, nil
+}
+
+// tabWriter writes records as tab separated cells of a tabwriter.Writer.
+type tabWriter struct {
+	w *tabwriter.Writer
+}
+
+func (t tabWriter) Write(record []string) error {
+	_, err := fmt.Fprintf(t.w, "%s\t\n", strings.Join(record, "\t"))
+	return err
+}
@@ -0,0 +1,61 @@
+package main
+
+import (
+	"bytes"
+	"encoding/csv"
+	"errors"
+	"flag"
+	"strconv"
+	"testing"
+)
+
+// failWriter fails every write.
+type failWriter struct{}
+
+func (failWriter) Write(p []byte) (int, error) {
+	return 0, errors.New("write failed")
+}
+
+func TestBench(t *testing.T) {
+	// testing.Benchmark runs for -test.benchtime, shortened for the test.
+	benchtime := flag.Lookup("test.benchtime").Value.String()
+	flag.Set("test.benchtime", "1ms")
+	defer flag.Set("test.benchtime", benchtime)
+	var stdout, stderr bytes.Buffer
+	status := run([]string{"bench", "-csv", "-encodings", "62,base58check", "-sizes", "8,64"}, nil, &stdout, &stderr)
+	if status != 0 {
+		t.Fatalf("bench got: %d %q.", status, stderr.String())
+	}
+	records, err := csv.NewReader(&stdout).ReadAll()
+	if err != nil || len(records) != 9 {
+		t.Fatalf("bench got: %q %v expected a header and 8 records.", records, err)
+	}
+	if records[0][0] != "encoding" || records[0][8] != "overhead" {
+		t.Errorf("bench header got: %q.", records[0])
+	}
+	exp := [][3]string{{"62", "8", "encode"}, {"62", "8", "decode"}, {"62", "64", "encode"}, {"62", "64", "decode"}, {"base58check", "8", "encode"}}
+	for i, e := range exp {
+		if r := records[i+1]; r[0] != e[0] || r[1] != e[1] || r[2] != e[2] {
+			t.Errorf("bench record %d got: %q expected: %q.", i, r, e)
+		}
+	}
+	// The encodings of 8 bytes are 11 base 62 digits and 11 + 6 base 58 digits with the checksum.
+	for i, l := range map[int]int{1: 11, 5: 17} {
+		if n, _ := strconv.Atoi(records[i][7]); n != l {
+			t.Errorf("bench record %d got encoded length: %s expected: %d.", i, records[i][7], l)
+		}
+	}
+
+	for _, args := range [][]string{{"bench", "-encodings", "nosuchencoding"}, {"bench", "-sizes", "0"}, {"bench", "-nosuchflag"}} {
+		stderr.Reset()
+		if status := run(args, nil, &stdout, &stderr); status != 2 || stderr.Len() == 0 {
+			t.Errorf("run(%q) got: %d %q expected status 2 and an error.", args, status, stderr.String())
+		}
+	}
+	for _, args := range [][]string{{"bench", "-csv", "-encodings", "62", "-sizes", "8"}, {"bench", "-encodings", "62", "-sizes", "8"}} {
+		stderr.Reset()
+		if status := run(args, nil, failWriter{}, &stderr); status != 2 || stderr.Len() == 0 {
+			t.Errorf("run(%q) to a failing writer got: %d %q expected status 2 and an error.", args, status, stderr.String())
+		}
+	}
+}
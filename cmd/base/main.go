@@ -0,0 +1,55 @@
+// Use of this source code is governed by the CC0 1.0
+// license that can be found in the LICENSE file or here:
+// http://creativecommons.org/publicdomain/zero/1.0/
+
+// Command base is a command line interface to the github.com/7i/base package.
+//
+// Usage:
+//
+//	base scan [flags] [path ...]
+//	base bench [flags]
+//
+// Scan reports possible secrets encoded with the alphabets of the package in the given files and directories, or in the standard input
+// if no path is given. Every finding is printed as file:line:column followed by the encoding, the entropy score and the token, or as
+// a JSON object per line with -json. The exit status is 1 if anything was found and 2 on errors.
+//
+// Bench measures the Encode and Decode throughput, allocations and size overhead of every registered encoding and every base
+// for random inputs of several sizes with testing.Benchmark, which runs every measurement for about a second. With -csv
+// the results are printed as CSV for tracking them over releases. The exit status is 2 on errors, including failed writes.
+package main
+
+import (
+	"fmt"
+	"io"
+	"os"
+)
+
+func main() {
+	os.Exit(run(os.Args[1:], os.Stdin, os.Stdout, os.Stderr))
+}
+
+const usage = `usage: base <command> [flags] [arguments]
+
+commands:
+  scan    report possible secrets in files
+  bench   measure the encodings
+`
+
+// run runs the command line args and returns the exit status.
+func run(args []string, stdin io.Reader, stdout, stderr io.Writer) int {
+	if len(args) == 0 {
+		fmt.Fprint(stderr, usage)
+		return 2
+	}
+	switch args[0] {
+	case "scan":
+		return scan(args[1:], stdin, stdout, stderr)
+	case "bench":
+		return bench(args[1:], stdout, stderr)
+	case "help", "-h", "-help", "--help":
+		fmt.Fprint(stdout, usage)
+		return 0
+	}
+	fmt.Fprintf(stderr, "base: unknown command %q\n%s", args[0], usage)
+	return 2
+}
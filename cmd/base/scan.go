@@ -0,0 +1,114 @@
+// Use of this source code is governed by the CC0 1.0
+// license that can be found in the LICENSE file or here:
+// http://creativecommons.org/publicdomain/zero/1.0/
+
+package main
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/7i/base"
+)
+
+// scan runs the scan command.
+func scan(args []string, stdin io.Reader, stdout, stderr io.Writer) int {
+	fl := flag.NewFlagSet("scan", flag.ContinueOnError)
+	fl.SetOutput(stderr)
+	encodings := fl.String("encodings", strings.Join(base.DefaultScanEncodings, ","), "comma separated `names` of the encodings to look for")
+	minLen := fl.Int("min-len", 20, "minimum token `length`")
+	minScore := fl.Float64("min-score", 0.85, "minimum entropy `score` of tokens without a checksum, between 0 and 1")
+	jsonOut := fl.Bool("json", false, "print findings as JSON objects, one per line")
+	fl.Usage = func() {
+		fmt.Fprintf(stderr, "usage: base scan [flags] [path ...]\n")
+		fl.PrintDefaults()
+	}
+	if err := fl.Parse(args); err != nil {
+		return 2
+	}
+	s := &base.Scanner{Encodings: strings.Split(*encodings, ","), MinLen: *minLen, MinScore: *minScore}
+	if _, err := s.Scan(strings.NewReader(""), ""); err != nil {
+		fmt.Fprintf(stderr, "base scan: %v\n", err)
+		return 2
+	}
+
+	status := 0
+	report := func(found []base.Finding) {
+		for _, f := range found {
+			status = max(status, 1)
+			if *jsonOut {
+				b, _ := json.Marshal(f)
+				fmt.Fprintf(stdout, "%s\n", b)
+				continue
+			}
+			verified := ""
+			if f.Verified {
+				verified = " verified"
+			}
+			fmt.Fprintf(stdout, "%s:%d:%d: %s score=%.2f%s %s\n", f.File, f.Line, f.Column, f.Encoding, f.Score, verified, f.Text)
+		}
+	}
+	fail := func(err error) {
+		fmt.Fprintf(stderr, "base scan: %v\n", err)
+		status = 2
+	}
+
+	if fl.NArg() == 0 {
+		found, err := s.Scan(stdin, "<stdin>")
+		report(found)
+		if err != nil {
+			fail(err)
+		}
+		return status
+	}
+	for _, root := range fl.Args() {
+		err := filepath.WalkDir(root, func(path string, d fs.DirEntry, err error) error {
+			if err != nil {
+				fail(err)
+				return nil
+			}
+			if d.IsDir() {
+				if d.Name() == ".git" && path != root {
+					return filepath.SkipDir
+				}
+				return nil
+			}
+			if !d.Type().IsRegular() {
+				return nil
+			}
+			found, err := scanFile(s, path)
+			report(found)
+			if err != nil {
+				fail(err)
+			}
+			return nil
+		})
+		if err != nil {
+			fail(err)
+		}
+	}
+	return status
+}
+
+// scanFile scans the file at path, binary files containing a null byte in their first 8000 bytes are skipped like git does.
+func scanFile(s *base.Scanner, path string) ([]base.Finding, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	r := bufio.NewReader(f)
+	head, _ := r.Peek(8000)
+	if bytes.IndexByte(head, 0) >= 0 {
+		return nil, nil
+	}
+	return s.Scan(r, path)
+}
@@ -0,0 +1,61 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestScan(t *testing.T) {
+	dir := t.TempDir()
+	files := map[string]string{
+		"app.env":          "NAME=example\nKEY=4eC39HqLyjWDarjtT1zdp7dcXq2\n",
+		"sub/wallet.txt":   "send to 16UwLL9Risc3QfPqBUvKofHmBQ7wMtjvM\n",
+		"sub/image.bin":    "\x00\x01 16UwLL9Risc3QfPqBUvKofHmBQ7wMtjvM",
+		".git/config":      "token 4eC39HqLyjWDarjtT1zdp7dcXq2",
+		"clean/readme.txt": "nothing to see here\n",
+	}
+	for name, content := range files {
+		path := filepath.Join(dir, name)
+		os.MkdirAll(filepath.Dir(path), 0o755)
+		if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	var stdout, stderr bytes.Buffer
+	status := run([]string{"scan", dir}, nil, &stdout, &stderr)
+	exp := filepath.Join(dir, "app.env") + ":2:5: 62 score=0.95 4eC39HqLyjWDarjtT1zdp7dcXq2\n" +
+		filepath.Join(dir, "sub/wallet.txt") + ":1:9: base58check score=0.90 verified 16UwLL9Risc3QfPqBUvKofHmBQ7wMtjvM\n"
+	if status != 1 || stdout.String() != exp || stderr.Len() != 0 {
+		t.Errorf("scan got: %d %q %q expected: 1 %q.", status, stdout.String(), stderr.String(), exp)
+	}
+
+	stdout.Reset()
+	status = run([]string{"scan", filepath.Join(dir, "clean")}, nil, &stdout, &stderr)
+	if status != 0 || stdout.Len() != 0 {
+		t.Errorf("scan of a clean directory got: %d %q.", status, stdout.String())
+	}
+
+	stdout.Reset()
+	status = run([]string{"scan", "-json", "-encodings", "base58check"}, strings.NewReader(files["sub/wallet.txt"]), &stdout, &stderr)
+	var f struct {
+		File     string
+		Line     int
+		Encoding string
+		Verified bool
+	}
+	if err := json.Unmarshal(stdout.Bytes(), &f); status != 1 || err != nil || f.File != "<stdin>" || f.Line != 1 || !f.Verified {
+		t.Errorf("scan -json got: %d %q.", status, stdout.String())
+	}
+
+	for _, args := range [][]string{{"scan", "-encodings", "base64"}, {"scan", "-nosuchflag"}, {"nosuchcommand"}, {}} {
+		stderr.Reset()
+		if status := run(args, strings.NewReader(""), &stdout, &stderr); status != 2 || stderr.Len() == 0 {
+			t.Errorf("run(%q) got: %d %q expected status 2 and an error.", args, status, stderr.String())
+		}
+	}
+}
@@ -0,0 +1,109 @@
+// Use of this source code is governed by the CC0 1.0
+// license that can be found in the LICENSE file or here:
+// http://creativecommons.org/publicdomain/zero/1.0/
+
+package base
+
+import (
+	"fmt"
+	"math/big"
+	"sort"
+)
+
+// Binomial returns the binomial coefficient n choose k, zero if k < 0 or k > n.
+func Binomial(n, k int) *big.Int {
+	if n < 0 || k < 0 || k > n {
+		return big.NewInt(0)
+	}
+	if k > n-k {
+		k = n - k
+	}
+	r := big.NewInt(1)
+	t := new(big.Int)
+	for i := 1; i <= k; i++ {
+		r.Mul(r, t.SetInt64(int64(n-k+i)))
+		r.Quo(r, t.SetInt64(int64(i)))
+	}
+	return r
+}
+
+// RankSubset returns the rank of the k-subset set of {0, ..., n-1} in the combinatorial number system,
+// where k is len(set). The rank is a number in the range [0, Binomial(n, k)).
+//
+// The elements of set must be distinct and in the range [0, n), their order does not matter.
+func RankSubset(set []int, n int) (*big.Int, error) {
+	s := append([]int(nil), set...)
+	sort.Ints(s)
+	for i, c := range s {
+		if c < 0 || c >= n {
+			return nil, fmt.Errorf("Illegal subset element %d for n %d.", c, n)
+		}
+		if i > 0 && s[i-1] == c {
+			return nil, fmt.Errorf("Illegal duplicate subset element %d.", c)
+		}
+	}
+
+	r := big.NewInt(0)
+	for i, c := range s {
+		r.Add(r, Binomial(c, i+1))
+	}
+	return r, nil
+}
+
+// UnrankSubset returns the k-subset of {0, ..., n-1} with rank r in the combinatorial number system in ascending order.
+//
+// r must be in the range [0, Binomial(n, k)).
+func UnrankSubset(r *big.Int, n, k int) ([]int, error) {
+	if n < 0 || k < 0 || k > n {
+		return nil, fmt.Errorf("Illegal subset size %d for n %d.", k, n)
+	}
+	if r.Sign() < 0 || r.Cmp(Binomial(n, k)) >= 0 {
+		return nil, fmt.Errorf("Subset rank out of range for n %d and k %d.", n, k)
+	}
+
+	s := make([]int, k)
+	rem := new(big.Int).Set(r)
+	t := new(big.Int)
+
+	// b is kept equal to Binomial(c, i) while c walks downwards, every
+	// step only needs a multiplication and a division by small integers.
+	c := n - 1
+	b := Binomial(c, k)
+	for i := k; i > 0; i-- {
+		for b.Cmp(rem) > 0 {
+			// Binomial(c-1, i) = Binomial(c, i) * (c-i) / c
+			b.Mul(b, t.SetInt64(int64(c-i)))
+			b.Quo(b, t.SetInt64(int64(c)))
+			c--
+		}
+		s[i-1] = c
+		rem.Sub(rem, b)
+		if i > 1 {
+			// Binomial(c-1, i-1) = Binomial(c, i) * i / c
+			b.Mul(b, t.SetInt64(int64(i)))
+			b.Quo(b, t.SetInt64(int64(c)))
+			c--
+		}
+	}
+	return s, nil
+}
+
+// EncodeSubset returns the rank of the k-subset set of {0, ..., n-1} encoded with the digits of a.
+//
+// See RankSubset for the requirements on set.
+func EncodeSubset(set []int, n int, a *Alphabet) ([]byte, error) {
+	r, err := RankSubset(set, n)
+	if err != nil {
+		return nil, err
+	}
+	return a.EncodeInt(r)
+}
+
+// DecodeSubset returns the k-subset of {0, ..., n-1} in ascending order represented by u, the rank encoded with the digits of a.
+func DecodeSubset(u []byte, n, k int, a *Alphabet) ([]int, error) {
+	r, err := a.DecodeInt(u)
+	if err != nil {
+		return nil, err
+	}
+	return UnrankSubset(r, n, k)
+}
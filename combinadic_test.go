@@ -0,0 +1,134 @@
+package base_test
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/7i/base"
+)
+
+func TestBinomial(t *testing.T) {
+	// Compare against Pascal's triangle
+	row := []int64{1}
+	for n := 0; n < 60; n++ {
+		for k := -1; k <= n+1; k++ {
+			exp := int64(0)
+			if k >= 0 && k <= n {
+				exp = row[k]
+			}
+			if res := base.Binomial(n, k); res.Int64() != exp {
+				t.Errorf("Binomial(%d, %d) got: %v expected: %d.", n, k, res, exp)
+			}
+		}
+		next := make([]int64, n+2)
+		next[0], next[n+1] = 1, 1
+		for k := 1; k <= n; k++ {
+			next[k] = row[k-1] + row[k]
+		}
+		row = next
+	}
+}
+
+// colex enumerates all k-subsets of {0, ..., n-1} in colexicographic order, which is the rank order of the combinatorial number system.
+func colex(n, k int) [][]int {
+	var r [][]int
+	for mask := 0; mask < 1<<n; mask++ {
+		var s []int
+		for i := 0; i < n; i++ {
+			if mask&(1<<i) != 0 {
+				s = append(s, i)
+			}
+		}
+		if len(s) == k {
+			r = append(r, s)
+		}
+	}
+	// Sort by the largest element first
+	less := func(a, b []int) bool {
+		for i := len(a) - 1; i >= 0; i-- {
+			if a[i] != b[i] {
+				return a[i] < b[i]
+			}
+		}
+		return false
+	}
+	for i := 1; i < len(r); i++ {
+		for j := i; j > 0 && less(r[j], r[j-1]); j-- {
+			r[j], r[j-1] = r[j-1], r[j]
+		}
+	}
+	return r
+}
+
+func TestRankSubset(t *testing.T) {
+	for n := 0; n <= 10; n++ {
+		for k := 0; k <= n; k++ {
+			for i, s := range colex(n, k) {
+				r, err := base.RankSubset(s, n)
+				if err != nil || r.Int64() != int64(i) {
+					t.Errorf("RankSubset(%v, %d) got: %v %v expected: %d.", s, n, r, err, i)
+				}
+				u, err := base.UnrankSubset(big.NewInt(int64(i)), n, k)
+				if err != nil || len(u) != len(s) {
+					t.Fatalf("UnrankSubset(%d, %d, %d) got: %v %v expected: %v.", i, n, k, u, err, s)
+				}
+				for j := range s {
+					if u[j] != s[j] {
+						t.Errorf("UnrankSubset(%d, %d, %d) got: %v expected: %v.", i, n, k, u, s)
+						break
+					}
+				}
+			}
+		}
+	}
+}
+
+func TestRankSubsetErrors(t *testing.T) {
+	for _, s := range [][]int{{-1}, {5}, {1, 1}} {
+		if _, err := base.RankSubset(s, 5); err == nil {
+			t.Errorf("RankSubset(%v, 5) succeeded, expected an error.", s)
+		}
+	}
+	if _, err := base.UnrankSubset(base.Binomial(5, 2), 5, 2); err == nil {
+		t.Errorf("UnrankSubset accepted a rank out of range.")
+	}
+	if _, err := base.UnrankSubset(big.NewInt(0), 5, 6); err == nil {
+		t.Errorf("UnrankSubset accepted k > n.")
+	}
+}
+
+func TestEncodeSubset(t *testing.T) {
+	a, _ := base.Digits(62)
+	// Largest and smallest 6 of 49 lottery rows
+	for _, s := range [][]int{{43, 44, 45, 46, 47, 48}, {0, 1, 2, 3, 4, 5}, {48, 3, 17, 22, 9, 31}} {
+		u, err := base.EncodeSubset(s, 49, a)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(u) > 4 {
+			t.Errorf("EncodeSubset(%v) got unexpectedly long code %s.", s, u)
+		}
+		d, err := base.DecodeSubset(u, 49, 6, a)
+		if err != nil {
+			t.Fatal(err)
+		}
+		r1, _ := base.RankSubset(s, 49)
+		r2, _ := base.RankSubset(d, 49)
+		if r1.Cmp(r2) != 0 {
+			t.Errorf("DecodeSubset(%s) got: %v expected: %v.", u, d, s)
+		}
+	}
+
+	// 200 features with 20 enabled
+	s := []int{0, 7, 13, 21, 34, 55, 89, 101, 120, 133, 144, 150, 160, 170, 180, 190, 195, 197, 198, 199}
+	u, _ := base.EncodeSubset(s, 200, a)
+	d, err := base.DecodeSubset(u, 200, len(s), a)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for i := range s {
+		if d[i] != s[i] {
+			t.Fatalf("DecodeSubset(%s) got: %v expected: %v.", u, d, s)
+		}
+	}
+}
@@ -0,0 +1,147 @@
+// Use of this source code is governed by the CC0 1.0
+// license that can be found in the LICENSE file or here:
+// http://creativecommons.org/publicdomain/zero/1.0/
+
+package base
+
+import (
+	"crypto/rand"
+	"crypto/sha3"
+	"fmt"
+	"io"
+	"math/big"
+	"os"
+	"strconv"
+	"sync"
+	"time"
+)
+
+const (
+	cuid2DefaultLength = 24
+	cuid2BigLength     = 32
+	cuid2CounterMax    = 476782367
+)
+
+// CUID2 generates collision resistant ids compatible with the JavaScript @paralleldrive/cuid2 package.
+//
+// Every id starts with a random lower case letter followed by base36 digits of the SHA3-512 hash of the current time,
+// random salt, a counter and a fingerprint of the generator. A CUID2 is safe for concurrent use.
+type CUID2 struct {
+	mu          sync.Mutex
+	length      int
+	counter     uint64
+	fingerprint string
+	rand        io.Reader
+	now         func() time.Time
+}
+
+var (
+	defaultCUID2     *CUID2
+	defaultCUID2Err  error
+	defaultCUID2Once sync.Once
+)
+
+// NewCUID2 returns a generator of ids with length characters, between 2 and 32. The default length of cuid2 is 24.
+func NewCUID2(length int) (*CUID2, error) {
+	host, _ := os.Hostname()
+	return newCUID2(rand.Reader, time.Now, length, host+strconv.Itoa(os.Getpid()))
+}
+
+func newCUID2(r io.Reader, now func() time.Time, length int, globals string) (*CUID2, error) {
+	if length < 2 || length > cuid2BigLength {
+		return nil, fmt.Errorf("Illegal CUID2 length %d.", length)
+	}
+	g := &CUID2{length: length, rand: r, now: now}
+	c, err := randIntn(r, cuid2CounterMax)
+	if err != nil {
+		return nil, err
+	}
+	g.counter = uint64(c)
+	e, err := g.entropy(cuid2BigLength)
+	if err != nil {
+		return nil, err
+	}
+	g.fingerprint = cuid2Hash(globals + e)[:cuid2BigLength]
+	return g, nil
+}
+
+// CreateCUID2 returns a new id of the default length 24 from a package level generator, like createId() of cuid2.
+func CreateCUID2() (string, error) {
+	defaultCUID2Once.Do(func() {
+		defaultCUID2, defaultCUID2Err = NewCUID2(cuid2DefaultLength)
+	})
+	if defaultCUID2Err != nil {
+		return "", defaultCUID2Err
+	}
+	return defaultCUID2.Next()
+}
+
+// Next returns a new id.
+func (g *CUID2) Next() (string, error) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	first, err := randIntn(g.rand, 26)
+	if err != nil {
+		return "", err
+	}
+	t, _ := base36.EncodeInt(big.NewInt(g.now().UnixMilli()))
+	salt, err := g.entropy(g.length)
+	if err != nil {
+		return "", err
+	}
+	count := strconv.FormatUint(g.counter, 36)
+	g.counter++
+
+	h := cuid2Hash(string(t) + salt + count + g.fingerprint)
+	return string(rune('a'+first)) + h[1:g.length], nil
+}
+
+// IsCUID2 reports whether id looks like a CUID2, 2 to 32 lower case base36 digits.
+func IsCUID2(id string) bool {
+	if len(id) < 2 || len(id) > cuid2BigLength {
+		return false
+	}
+	for i := 0; i < len(id); i++ {
+		if c := id[i]; !('0' <= c && c <= '9' || 'a' <= c && c <= 'z') {
+			return false
+		}
+	}
+	return true
+}
+
+// entropy returns length random base36 digits.
+func (g *CUID2) entropy(length int) (string, error) {
+	b := make([]byte, length)
+	for i := range b {
+		v, err := randIntn(g.rand, 36)
+		if err != nil {
+			return "", err
+		}
+		b[i] = digits[v]
+	}
+	return string(b), nil
+}
+
+// cuid2Hash returns the SHA3-512 hash of s as a base36 integer without its first, biased, digit.
+func cuid2Hash(s string) string {
+	h := sha3.Sum512([]byte(s))
+	d, _ := base36.EncodeInt(new(big.Int).SetBytes(h[:]))
+	return string(d[1:])
+}
+
+// randIntn returns a uniformly distributed random integer in [0, n) read from r, n must be positive and less than 2^31.
+func randIntn(r io.Reader, n int) (int, error) {
+	// Reject values in the incomplete last interval to avoid modulo bias.
+	limit := uint32(1<<32 - (1<<32)%uint64(n))
+	var b [4]byte
+	for {
+		if _, err := io.ReadFull(r, b[:]); err != nil {
+			return 0, err
+		}
+		v := uint32(b[0])<<24 | uint32(b[1])<<16 | uint32(b[2])<<8 | uint32(b[3])
+		if limit == 0 || v < limit {
+			return int(v % uint32(n)), nil
+		}
+	}
+}
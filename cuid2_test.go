@@ -0,0 +1,71 @@
+package base_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/7i/base"
+)
+
+type zeroReader struct{}
+
+func (zeroReader) Read(b []byte) (int, error) {
+	clear(b)
+	return len(b), nil
+}
+
+func TestCUID2Hash(t *testing.T) {
+	// The SHA3-512 hash of the empty string in base36 without its first digit, as computed by cuid2's hash("")
+	exp := "hwy9hczxnhp8h02w8vk5ozzfbicdzl7bm3tokbnp700ruweb66gvvn2smv2u019fy0avhunqj6eta7kgi9qwexyqb5aufudz52"
+	if res := base.CUID2Hash(""); res != exp {
+		t.Errorf("CUID2Hash(\"\") got: %s expected: %s.", res, exp)
+	}
+}
+
+// The expected ids are what cuid2's init() returns with the same fingerprint, clock, counter and random values.
+func TestCUID2Compatible(t *testing.T) {
+	now := func() time.Time { return time.UnixMilli(1700000000000) }
+	g, err := base.NewCUID2From(zeroReader{}, now, 24, "test")
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, exp := range []string{"aa13lusrp6jr619j7ypvien4", "axe6jrdu1r3d9g39wl2u4nuf"} {
+		id, err := g.Next()
+		if err != nil || id != exp {
+			t.Errorf("CUID2 Next got: %q %v expected: %q.", id, err, exp)
+		}
+	}
+
+	g, _ = base.NewCUID2From(zeroReader{}, now, 10, "test")
+	if id, _ := g.Next(); id != "axgdrz3bic" {
+		t.Errorf("CUID2 Next got: %q expected: \"axgdrz3bic\".", id)
+	}
+}
+
+func TestCUID2(t *testing.T) {
+	seen := map[string]bool{}
+	for i := 0; i < 1000; i++ {
+		id, err := base.CreateCUID2()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(id) != 24 || !base.IsCUID2(id) || id[0] < 'a' || id[0] > 'z' {
+			t.Errorf("CreateCUID2 got malformed id %q.", id)
+		}
+		if seen[id] {
+			t.Errorf("CreateCUID2 returned %q twice.", id)
+		}
+		seen[id] = true
+	}
+
+	for _, l := range []int{1, 33} {
+		if _, err := base.NewCUID2(l); err == nil {
+			t.Errorf("NewCUID2(%d) succeeded, expected an error.", l)
+		}
+	}
+	for _, id := range []string{"", "a", "aBc", "a-b", "abcdefghijklmnopqrstuvwxyz0123456"} {
+		if base.IsCUID2(id) {
+			t.Errorf("IsCUID2(%q) got true.", id)
+		}
+	}
+}
@@ -0,0 +1,283 @@
+// Use of this source code is governed by the CC0 1.0
+// license that can be found in the LICENSE file or here:
+// http://creativecommons.org/publicdomain/zero/1.0/
+
+package base
+
+import (
+	"fmt"
+	"math/big"
+	"sort"
+)
+
+// Curve is a space filling curve mapping points with N coordinates of a fixed number of bits to a single integer key.
+//
+// Points close to each other tend to get keys close to each other, and every aligned cube of side 2^l covers one contiguous range of keys.
+type Curve int
+
+const (
+	// Morton is the Z-order curve, the key interleaves the bits of all coordinates with the first coordinate as the most significant.
+	Morton Curve = iota
+	// Hilbert is the N dimensional Hilbert curve, consecutive keys are always neighbouring points.
+	Hilbert
+)
+
+// KeyRange is the inclusive range of keys [Lo, Hi].
+type KeyRange struct {
+	Lo, Hi *big.Int
+}
+
+// Key returns the key of the point coords where every coordinate has the given number of bits, between 1 and 64.
+// The key has len(coords)*bits bits. Use 32 bits for uint32 coordinates.
+func (c Curve) Key(coords []uint64, bits int) (*big.Int, error) {
+	if err := c.check(len(coords), bits); err != nil {
+		return nil, err
+	}
+	x := append([]uint64(nil), coords...)
+	for _, v := range x {
+		if bits < 64 && v>>uint(bits) != 0 {
+			return nil, fmt.Errorf("Coordinate %d does not fit in %d bits.", v, bits)
+		}
+	}
+	if c == Hilbert {
+		axesToTranspose(x, bits)
+	}
+	return interleave(x, bits), nil
+}
+
+// Coords returns the point with dims coordinates of the given number of bits that has the key key.
+func (c Curve) Coords(key *big.Int, dims, bits int) ([]uint64, error) {
+	if err := c.check(dims, bits); err != nil {
+		return nil, err
+	}
+	if key.Sign() < 0 || key.BitLen() > dims*bits {
+		return nil, fmt.Errorf("Key out of range for %d coordinates of %d bits.", dims, bits)
+	}
+	x := deinterleave(key, dims, bits)
+	if c == Hilbert {
+		transposeToAxes(x, bits)
+	}
+	return x, nil
+}
+
+// EncodeKey returns the key of the point coords as a fixed width string of digits of a whose byte order matches the key order.
+//
+// a must be sorted, see Alphabet.Sorted.
+func (c Curve) EncodeKey(coords []uint64, bits int, a *Alphabet) ([]byte, error) {
+	if !a.Sorted() {
+		return nil, fmt.Errorf("Illegal unsorted alphabet for sortable encoding.")
+	}
+	k, err := c.Key(coords, bits)
+	if err != nil {
+		return nil, err
+	}
+	return a.EncodeFixed(k, a.Width(len(coords)*bits))
+}
+
+// DecodeKey returns the point with dims coordinates of the given number of bits encoded in u by EncodeKey.
+func (c Curve) DecodeKey(u []byte, dims, bits int, a *Alphabet) ([]uint64, error) {
+	if w := a.Width(dims * bits); len(u) != w {
+		return nil, fmt.Errorf("Illegal key length %d, expected %d.", len(u), w)
+	}
+	k, err := a.DecodeInt(u)
+	if err != nil {
+		return nil, err
+	}
+	return c.Coords(k, dims, bits)
+}
+
+// DefaultRangesLimit is the maximum number of cubes of the exact decomposition of Ranges if its maxRanges is 0.
+const DefaultRangesLimit = 1 << 16
+
+// Ranges returns sorted, non overlapping key ranges that together cover every point of the box with the inclusive corners lo and hi.
+//
+// The box is decomposed into aligned cubes. If maxRanges is positive at most maxRanges ranges are returned, the decomposition
+// then stops early and the ranges may also cover keys outside of the box that have to be filtered by the caller.
+// Otherwise the ranges cover exactly the box, and Ranges returns an error if that takes more than DefaultRangesLimit cubes,
+// as for unaligned boxes with many bits. Ranges supports at most 16 coordinates.
+func (c Curve) Ranges(lo, hi []uint64, bits, maxRanges int) ([]KeyRange, error) {
+	dims := len(lo)
+	if err := c.check(dims, bits); err != nil {
+		return nil, err
+	}
+	if len(hi) != dims || dims > 16 {
+		return nil, fmt.Errorf("Illegal box dimensions %d and %d.", len(lo), len(hi))
+	}
+	for i := range lo {
+		if lo[i] > hi[i] || bits < 64 && hi[i]>>uint(bits) != 0 {
+			return nil, fmt.Errorf("Illegal box coordinates %d and %d.", lo[i], hi[i])
+		}
+	}
+
+	type cell struct {
+		corner []uint64
+		level  int
+	}
+	var full, partial []cell
+	partial = append(partial, cell{make([]uint64, dims), bits})
+	for len(partial) > 0 {
+		var next []cell
+		for _, cl := range partial {
+			ext := uint64(1)<<uint(cl.level) - 1
+			inside, overlap := true, true
+			for i, v := range cl.corner {
+				if v > hi[i] || v+ext < lo[i] {
+					overlap = false
+					break
+				}
+				if v < lo[i] || v+ext > hi[i] {
+					inside = false
+				}
+			}
+			switch {
+			case !overlap:
+			case inside:
+				full = append(full, cl)
+			default:
+				next = append(next, cl)
+			}
+		}
+		partial = next
+		n := len(full) + len(partial)<<uint(dims)
+		if len(partial) == 0 || maxRanges > 0 && n > maxRanges {
+			break
+		}
+		if maxRanges <= 0 && n > DefaultRangesLimit {
+			return nil, fmt.Errorf("Too many cubes for the box, more than %d.", DefaultRangesLimit)
+		}
+		// Split every partially covered cell in its 2^dims children.
+		next = nil
+		for _, cl := range partial {
+			half := uint64(1) << uint(cl.level-1)
+			for m := 0; m < 1<<uint(dims); m++ {
+				child := append([]uint64(nil), cl.corner...)
+				for i := range child {
+					if m>>uint(i)&1 == 1 {
+						child[i] += half
+					}
+				}
+				next = append(next, cell{child, cl.level - 1})
+			}
+		}
+		partial = next
+	}
+
+	// Cells left in partial are only partially covered, they are kept whole.
+	var r []KeyRange
+	for _, cl := range append(full, partial...) {
+		k, _ := c.Key(cl.corner, bits)
+		n := uint(dims * cl.level)
+		lo := new(big.Int).Rsh(k, n)
+		lo.Lsh(lo, n)
+		hi := new(big.Int).Lsh(big.NewInt(1), n)
+		hi.Sub(hi, big.NewInt(1)).Add(hi, lo)
+		r = append(r, KeyRange{lo, hi})
+	}
+
+	sort.Slice(r, func(i, j int) bool { return r[i].Lo.Cmp(r[j].Lo) < 0 })
+	merged := r[:0]
+	one := big.NewInt(1)
+	for _, kr := range r {
+		if n := len(merged); n > 0 {
+			if next := new(big.Int).Add(merged[n-1].Hi, one); next.Cmp(kr.Lo) == 0 {
+				merged[n-1].Hi = kr.Hi
+				continue
+			}
+		}
+		merged = append(merged, kr)
+	}
+	return merged, nil
+}
+
+func (c Curve) check(dims, bits int) error {
+	if c != Morton && c != Hilbert {
+		return fmt.Errorf("Illegal curve %d.", c)
+	}
+	if dims < 1 || bits < 1 || bits > 64 {
+		return fmt.Errorf("Illegal curve dimensions %d with %d bits.", dims, bits)
+	}
+	return nil
+}
+
+// interleave returns the integer whose bits are bit bits-1 of x[0], x[1], ..., then bit bits-2 of x[0], x[1], ... and so on.
+func interleave(x []uint64, bits int) *big.Int {
+	total := len(x) * bits
+	buf := make([]byte, (total+7)/8)
+	p := 0
+	for b := bits - 1; b >= 0; b-- {
+		for _, v := range x {
+			if v>>uint(b)&1 == 1 {
+				i := total - 1 - p
+				buf[len(buf)-1-i/8] |= 1 << uint(i%8)
+			}
+			p++
+		}
+	}
+	return new(big.Int).SetBytes(buf)
+}
+
+// deinterleave is the inverse of interleave.
+func deinterleave(k *big.Int, dims, bits int) []uint64 {
+	x := make([]uint64, dims)
+	p := dims*bits - 1
+	for b := bits - 1; b >= 0; b-- {
+		for d := range x {
+			x[d] |= uint64(k.Bit(p)) << uint(b)
+			p--
+		}
+	}
+	return x
+}
+
+// axesToTranspose converts coordinates to the transposed Hilbert index, J. Skilling, "Programming the Hilbert curve", 2004.
+func axesToTranspose(x []uint64, bits int) {
+	m := uint64(1) << uint(bits-1)
+	for q := m; q > 1; q >>= 1 {
+		p := q - 1
+		for i := range x {
+			if x[i]&q != 0 {
+				x[0] ^= p
+			} else {
+				t := (x[0] ^ x[i]) & p
+				x[0] ^= t
+				x[i] ^= t
+			}
+		}
+	}
+	// Gray encode
+	for i := 1; i < len(x); i++ {
+		x[i] ^= x[i-1]
+	}
+	t := uint64(0)
+	for q := m; q > 1; q >>= 1 {
+		if x[len(x)-1]&q != 0 {
+			t ^= q - 1
+		}
+	}
+	for i := range x {
+		x[i] ^= t
+	}
+}
+
+// transposeToAxes is the inverse of axesToTranspose.
+func transposeToAxes(x []uint64, bits int) {
+	n := uint64(2) << uint(bits-1)
+	// Gray decode
+	t := x[len(x)-1] >> 1
+	for i := len(x) - 1; i > 0; i-- {
+		x[i] ^= x[i-1]
+	}
+	x[0] ^= t
+	for q := uint64(2); q != n; q <<= 1 {
+		p := q - 1
+		for i := len(x) - 1; i >= 0; i-- {
+			if x[i]&q != 0 {
+				x[0] ^= p
+			} else {
+				t := (x[0] ^ x[i]) & p
+				x[0] ^= t
+				x[i] ^= t
+			}
+		}
+	}
+}
@@ -0,0 +1,194 @@
+package base_test
+
+import (
+	"bytes"
+	"math"
+	"math/big"
+	"testing"
+
+	"github.com/7i/base"
+)
+
+func TestMortonKnown(t *testing.T) {
+	tests := []struct {
+		coords []uint64
+		bits   int
+		key    int64
+	}{
+		{[]uint64{0, 0}, 2, 0},
+		{[]uint64{1, 0}, 2, 2},
+		{[]uint64{0, 1}, 2, 1},
+		{[]uint64{3, 0}, 2, 10},
+		{[]uint64{3, 3}, 2, 15},
+		{[]uint64{5, 3, 1}, 3, 0x117},
+	}
+	for _, test := range tests {
+		k, err := base.Morton.Key(test.coords, test.bits)
+		if err != nil || k.Int64() != test.key {
+			t.Errorf("Morton.Key(%v, %d) got: %v %v expected: %d.", test.coords, test.bits, k, err, test.key)
+		}
+	}
+}
+
+func TestCurveBijection(t *testing.T) {
+	for _, c := range []base.Curve{base.Morton, base.Hilbert} {
+		for _, dims := range []int{1, 2, 3} {
+			bits := 4
+			if dims == 3 {
+				bits = 3
+			}
+			n := int64(1) << uint(dims*bits)
+			var prev []uint64
+			seen := make(map[[3]uint64]bool)
+			for k := int64(0); k < n; k++ {
+				p, err := c.Coords(big.NewInt(k), dims, bits)
+				if err != nil {
+					t.Fatal(err)
+				}
+				var q [3]uint64
+				copy(q[:], p)
+				if seen[q] {
+					t.Fatalf("Curve %d maps two keys to %v.", c, p)
+				}
+				seen[q] = true
+				back, err := c.Key(p, bits)
+				if err != nil || back.Int64() != k {
+					t.Errorf("Curve %d Key(%v) got: %v %v expected: %d.", c, p, back, err, k)
+				}
+				// Consecutive Hilbert keys are neighbouring points
+				if c == base.Hilbert && prev != nil {
+					d := uint64(0)
+					for i := range p {
+						if p[i] > prev[i] {
+							d += p[i] - prev[i]
+						} else {
+							d += prev[i] - p[i]
+						}
+					}
+					if d != 1 {
+						t.Errorf("Hilbert keys %d and %d map to %v and %v which are not neighbours.", k-1, k, prev, p)
+					}
+				}
+				prev = p
+			}
+		}
+	}
+}
+
+func TestCurveWide(t *testing.T) {
+	a, _ := base.Digits(16)
+	for _, c := range []base.Curve{base.Morton, base.Hilbert} {
+		points := [][]uint64{
+			{0, 0, 0},
+			{math.MaxUint64, 0, 1},
+			{math.MaxUint64, math.MaxUint64, math.MaxUint64},
+			{0x0123456789abcdef, 0xfedcba9876543210, 42},
+		}
+		var keys [][]byte
+		for _, p := range points {
+			u, err := c.EncodeKey(p, 64, a)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if len(u) != 48 {
+				t.Errorf("Curve %d EncodeKey(%v) got length %d expected 48.", c, p, len(u))
+			}
+			back, err := c.DecodeKey(u, 3, 64, a)
+			if err != nil {
+				t.Fatal(err)
+			}
+			for i := range p {
+				if back[i] != p[i] {
+					t.Errorf("Curve %d DecodeKey(%s) got: %v expected: %v.", c, u, back, p)
+					break
+				}
+			}
+			keys = append(keys, u)
+		}
+		// Byte order of the encoded keys matches the key order
+		for i := range points {
+			for j := range points {
+				ki, _ := c.Key(points[i], 64)
+				kj, _ := c.Key(points[j], 64)
+				if ki.Cmp(kj) != bytes.Compare(keys[i], keys[j]) {
+					t.Errorf("Curve %d encoded keys %s and %s do not sort like their keys.", c, keys[i], keys[j])
+				}
+			}
+		}
+	}
+}
+
+func TestCurveRanges(t *testing.T) {
+	const bits = 4
+	boxes := [][2][]uint64{
+		{{0, 0}, {15, 15}},
+		{{3, 5}, {9, 6}},
+		{{1, 1}, {14, 14}},
+		{{7, 0}, {8, 15}},
+		{{5, 5}, {5, 5}},
+	}
+	for _, c := range []base.Curve{base.Morton, base.Hilbert} {
+		for _, box := range boxes {
+			lo, hi := box[0], box[1]
+			for _, limit := range []int{0, 4, 16} {
+				ranges, err := c.Ranges(lo, hi, bits, limit)
+				if err != nil {
+					t.Fatal(err)
+				}
+				if limit > 0 && len(ranges) > limit {
+					t.Errorf("Curve %d Ranges(%v, %v, %d) returned %d ranges.", c, lo, hi, limit, len(ranges))
+				}
+				for i := 1; i < len(ranges); i++ {
+					if ranges[i-1].Hi.Cmp(ranges[i].Lo) >= 0 {
+						t.Errorf("Curve %d Ranges(%v, %v) are not sorted and disjoint.", c, lo, hi)
+					}
+				}
+				for x := uint64(0); x < 1<<bits; x++ {
+					for y := uint64(0); y < 1<<bits; y++ {
+						k, _ := c.Key([]uint64{x, y}, bits)
+						covered := false
+						for _, r := range ranges {
+							if r.Lo.Cmp(k) <= 0 && k.Cmp(r.Hi) <= 0 {
+								covered = true
+							}
+						}
+						in := lo[0] <= x && x <= hi[0] && lo[1] <= y && y <= hi[1]
+						if in && !covered || limit == 0 && covered && !in {
+							t.Errorf("Curve %d Ranges(%v, %v, %d) point (%d, %d) in box %v covered %v.", c, lo, hi, limit, x, y, in, covered)
+						}
+					}
+				}
+			}
+		}
+	}
+}
+
+func TestCurveErrors(t *testing.T) {
+	if _, err := base.Morton.Key([]uint64{16}, 4); err == nil {
+		t.Errorf("Morton.Key accepted a coordinate out of range.")
+	}
+	if _, err := base.Hilbert.Key(nil, 4); err == nil {
+		t.Errorf("Hilbert.Key accepted zero dimensions.")
+	}
+	if _, err := base.Hilbert.Coords(big.NewInt(256), 2, 4); err == nil {
+		t.Errorf("Hilbert.Coords accepted a key out of range.")
+	}
+	if _, err := base.Curve(5).Key([]uint64{1}, 4); err == nil {
+		t.Errorf("Key accepted an illegal curve.")
+	}
+	if _, err := base.Morton.Ranges([]uint64{4}, []uint64{3}, 4, 0); err == nil {
+		t.Errorf("Ranges accepted an empty box.")
+	}
+	// The exact ranges of an unaligned box with 64 bit coordinates take too many cubes, a limit makes them approximate.
+	lo, hi := []uint64{1, 1}, []uint64{1<<64 - 2, 1<<64 - 2}
+	if _, err := base.Hilbert.Ranges(lo, hi, 64, 0); err == nil {
+		t.Errorf("Ranges of an unaligned 64 bit box succeeded, expected an error.")
+	}
+	if r, err := base.Hilbert.Ranges(lo, hi, 64, 100); err != nil || len(r) == 0 || len(r) > 100 {
+		t.Errorf("Ranges of an unaligned 64 bit box with a limit got: %d ranges %v.", len(r), err)
+	}
+	b62, _ := base.Digits(62)
+	if _, err := base.Morton.EncodeKey([]uint64{1}, 4, b62); err == nil {
+		t.Errorf("EncodeKey accepted an unsorted alphabet.")
+	}
+}
@@ -0,0 +1,103 @@
+// Use of this source code is governed by the CC0 1.0
+// license that can be found in the LICENSE file or here:
+// http://creativecommons.org/publicdomain/zero/1.0/
+
+package base
+
+import (
+	"fmt"
+)
+
+// maxDeBruijn is the largest supported De Bruijn sequence length.
+const maxDeBruijn = 1 << 28
+
+// DeBruijn is a De Bruijn sequence B(k, n) over an Alphabet with k digits, a cyclic sequence where every string of length n appears exactly once.
+type DeBruijn struct {
+	a   *Alphabet
+	n   int
+	seq []byte
+	// pos maps the value of every length n string, read as a base k number, to its offset in seq.
+	pos []int32
+}
+
+// NewDeBruijn returns the lexicographically smallest De Bruijn sequence B(k, n) over a, where k is a.Len().
+//
+// The sequence has length k^n which may not exceed 2^28.
+func NewDeBruijn(a *Alphabet, n int) (*DeBruijn, error) {
+	k := a.Len()
+	if n < 1 {
+		return nil, fmt.Errorf("Illegal De Bruijn order %d.", n)
+	}
+	size := 1
+	for i := 0; i < n; i++ {
+		if size > maxDeBruijn/k {
+			return nil, fmt.Errorf("De Bruijn sequence B(%d, %d) too large.", k, n)
+		}
+		size *= k
+	}
+
+	d := &DeBruijn{a: a, n: n, seq: make([]byte, 0, size), pos: make([]int32, size)}
+
+	// Concatenate all Lyndon words with a length dividing n in
+	// lexicographic order (Fredricksen, Kessler and Maiorana).
+	w := make([]int, n+1)
+	var gen func(t, p int)
+	gen = func(t, p int) {
+		if t > n {
+			if n%p == 0 {
+				for _, v := range w[1 : p+1] {
+					d.seq = append(d.seq, a.chars[v])
+				}
+			}
+			return
+		}
+		w[t] = w[t-p]
+		gen(t+1, p)
+		for j := w[t-p] + 1; j < k; j++ {
+			w[t] = j
+			gen(t+1, t)
+		}
+	}
+	gen(1, 1)
+
+	// Index every window with a rolling base k value.
+	v := 0
+	for i := 0; i < n-1; i++ {
+		v = v*k + int(a.dec[d.seq[i]])
+	}
+	for i := range d.seq {
+		v = v%(size/k)*k + int(a.dec[d.seq[(i+n-1)%size]])
+		d.pos[v] = int32(i)
+	}
+	return d, nil
+}
+
+// Bytes returns the cyclic De Bruijn sequence of length k^n.
+func (d *DeBruijn) Bytes() []byte {
+	return append([]byte(nil), d.seq...)
+}
+
+// Linear returns the De Bruijn sequence followed by its first n-1 characters,
+// the shortest string containing every string of length n exactly once as a substring.
+func (d *DeBruijn) Linear() []byte {
+	return append(d.Bytes(), d.seq[:d.n-1]...)
+}
+
+// Index returns the offset in the cyclic sequence where the string s of length n starts.
+//
+// Index runs in O(n) time using a table built by NewDeBruijn, it never scans the sequence.
+func (d *DeBruijn) Index(s []byte) (int, error) {
+	if len(s) != d.n {
+		return 0, fmt.Errorf("Illegal De Bruijn substring length %d, expected %d.", len(s), d.n)
+	}
+	k := d.a.Len()
+	v := 0
+	for _, c := range s {
+		x := d.a.dec[c]
+		if x < 0 {
+			return 0, fmt.Errorf("Illegal character %q in alphabet decoding.", c)
+		}
+		v = v*k + int(x)
+	}
+	return int(d.pos[v]), nil
+}
@@ -0,0 +1,84 @@
+package base_test
+
+import (
+	"testing"
+
+	"github.com/7i/base"
+)
+
+func TestDeBruijnKnown(t *testing.T) {
+	a, _ := base.Digits(2)
+	tests := map[int]string{
+		1: "01",
+		3: "00010111",
+		4: "0000100110101111",
+	}
+	for n, exp := range tests {
+		d, err := base.NewDeBruijn(a, n)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if res := string(d.Bytes()); res != exp {
+			t.Errorf("NewDeBruijn(2, %d) got: %s expected: %s.", n, res, exp)
+		}
+	}
+
+	c, _ := base.NewAlphabet("ACGT")
+	d, _ := base.NewDeBruijn(c, 2)
+	if res := string(d.Linear()); res != "AACAGATCCGCTGGTTA" {
+		t.Errorf("NewDeBruijn(ACGT, 2) got: %s expected: AACAGATCCGCTGGTTA.", res)
+	}
+}
+
+func TestDeBruijnIndex(t *testing.T) {
+	custom, _ := base.NewAlphabet("*#0123456789")
+	d10, _ := base.Digits(10)
+	d36, _ := base.Digits(36)
+	tests := []struct {
+		a *base.Alphabet
+		n int
+	}{{d10, 4}, {custom, 3}, {d36, 3}}
+	for _, test := range tests {
+		d, err := base.NewDeBruijn(test.a, test.n)
+		if err != nil {
+			t.Fatal(err)
+		}
+		seq := d.Bytes()
+		lin := d.Linear()
+		seen := make(map[string]bool)
+		for i := range seq {
+			s := lin[i : i+test.n]
+			if seen[string(s)] {
+				t.Fatalf("NewDeBruijn(%d, %d) contains %s twice.", test.a.Len(), test.n, s)
+			}
+			seen[string(s)] = true
+			if p, err := d.Index(s); err != nil || p != i {
+				t.Errorf("Index(%s) got: %d %v expected: %d.", s, p, err, i)
+			}
+		}
+		exp := 1
+		for i := 0; i < test.n; i++ {
+			exp *= test.a.Len()
+		}
+		if len(seen) != exp {
+			t.Errorf("NewDeBruijn(%d, %d) covers %d strings, expected %d.", test.a.Len(), test.n, len(seen), exp)
+		}
+	}
+}
+
+func TestDeBruijnErrors(t *testing.T) {
+	a, _ := base.Digits(62)
+	if _, err := base.NewDeBruijn(a, 0); err == nil {
+		t.Errorf("NewDeBruijn accepted order 0.")
+	}
+	if _, err := base.NewDeBruijn(a, 6); err == nil {
+		t.Errorf("NewDeBruijn accepted a too large sequence.")
+	}
+	d, _ := base.NewDeBruijn(a, 2)
+	if _, err := d.Index([]byte("abc")); err == nil {
+		t.Errorf("Index accepted a string of wrong length.")
+	}
+	if _, err := d.Index([]byte("a-")); err == nil {
+		t.Errorf("Index accepted an illegal character.")
+	}
+}
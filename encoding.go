@@ -0,0 +1,164 @@
+// Use of this source code is governed by the CC0 1.0
+// license that can be found in the LICENSE file or here:
+// http://creativecommons.org/publicdomain/zero/1.0/
+
+package base
+
+import (
+	"fmt"
+	"math/big"
+	"reflect"
+	"sort"
+	"strconv"
+	"sync"
+	"time"
+)
+
+// Encoding is implemented by every named encoding of binary data to text in the package, see Lookup.
+//
+// *Alphabet implements Encoding as a positional big number encoding just like Encode and Decode.
+type Encoding interface {
+	// Encode returns the encoding of src.
+	Encode(src []byte) []byte
+	// Decode returns the data encoded in src.
+	Decode(src []byte) ([]byte, error)
+}
+
+var registry = struct {
+	sync.RWMutex
+	m map[string]Encoding
+	// names holds the first name in sorted order of every registered comparable encoding, see eventName.
+	names map[Encoding]string
+}{names: map[Encoding]string{}, m: map[string]Encoding{
+	"crockford":      Crockford,
+	"shortuuid":      ShortUUIDAlphabet,
+	"nix32":          Nix32,
+	"rfc1924":        RFC1924,
+	"git85":          GitBase85,
+	"crockfordcheck": CrockfordCheck,
+	"base58check":    Base58Check,
+}}
+
+func init() {
+	registry.Lock()
+	defer registry.Unlock()
+	for name, e := range registry.m {
+		addName(name, e)
+	}
+}
+
+// addName records name in registry.names, registry must be locked.
+func addName(name string, e Encoding) {
+	if !reflect.ValueOf(e).Comparable() {
+		return
+	}
+	if old, ok := registry.names[e]; !ok || name < old {
+		registry.names[e] = name
+	}
+}
+
+// Register makes e available by name through Lookup. Register panics if name is already registered or e is nil.
+func Register(name string, e Encoding) {
+	registry.Lock()
+	defer registry.Unlock()
+	if e == nil {
+		panic("base: Register encoding is nil")
+	}
+	if _, dup := registry.m[name]; dup || isBaseName(name) {
+		panic("base: Register called twice for encoding " + name)
+	}
+	registry.m[name] = e
+	addName(name, e)
+}
+
+// Lookup returns the encoding registered as name.
+//
+// The names "2" to "62" are always available and return Digits of that base.
+func Lookup(name string) (Encoding, error) {
+	if isBaseName(name) {
+		b, _ := strconv.Atoi(name)
+		return Digits(b)
+	}
+	registry.RLock()
+	e, ok := registry.m[name]
+	registry.RUnlock()
+	if !ok {
+		return nil, fmt.Errorf("Unknown encoding %q.", name)
+	}
+	return e, nil
+}
+
+// Names returns the sorted names of all registered encodings, excluding the numeric names of Digits.
+func Names() []string {
+	registry.RLock()
+	defer registry.RUnlock()
+	names := make([]string, 0, len(registry.m))
+	for name := range registry.m {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// eventName returns the Encoding of the events of e, an encoding of the package, see Event.
+func eventName(e Encoding) string {
+	a, _ := e.(*Alphabet)
+	if a != nil && a.name != "" {
+		return a.name
+	}
+	registry.RLock()
+	name := registry.names[e]
+	registry.RUnlock()
+	switch {
+	case name != "":
+		return name
+	case a != nil:
+		return "alphabet" + strconv.Itoa(len(a.chars))
+	}
+	return "encoding"
+}
+
+// isBaseName reports whether name is one of the numeric names "2" to "62".
+func isBaseName(name string) bool {
+	b, err := strconv.Atoi(name)
+	return err == nil && b >= 2 && b <= len(digits) && strconv.Itoa(b) == name
+}
+
+// Encode takes an []byte u containing byte data and returns []byte r containing u encoded with the digits of a.
+//
+// Take note that Encode will remove any null bytes in the start of u, if this is important for your application save original size of the raw string.
+func (a *Alphabet) Encode(u []byte) (r []byte) {
+	if o := loadObserver(); o != nil {
+		defer observe(o, OpEncode, eventName(a), len(u), time.Now(), &r, nil)
+	}
+	return a.encode(u)
+}
+
+// encode is Encode without an Event, for the encodings built on a.
+func (a *Alphabet) encode(u []byte) []byte {
+	n := new(big.Int).SetBytes(u)
+	if n.Sign() == 0 {
+		return []byte{}
+	}
+	r, _ := a.EncodeInt(n)
+	return r
+}
+
+// Decode takes an []byte u containing data encoded with the digits of a and returns []byte r containing byte data.
+//
+// Take note that Decode will remove any null bytes in the start of r, if this is important for your application save original size of the raw string.
+func (a *Alphabet) Decode(u []byte) (r []byte, err error) {
+	if o := loadObserver(); o != nil {
+		defer observe(o, OpDecode, eventName(a), len(u), time.Now(), &r, &err)
+	}
+	return a.decode(u)
+}
+
+// decode is Decode without an Event.
+func (a *Alphabet) decode(u []byte) ([]byte, error) {
+	n, err := a.DecodeInt(u)
+	if err != nil {
+		return nil, err
+	}
+	return n.Bytes(), nil
+}
@@ -0,0 +1,73 @@
+package base_test
+
+import (
+	"strconv"
+	"testing"
+
+	"github.com/7i/base"
+)
+
+func TestLookup(t *testing.T) {
+	for b := 2; b < 63; b++ {
+		e, err := base.Lookup(strconv.Itoa(b))
+		if err != nil {
+			t.Fatal(err)
+		}
+		res := e.Encode(decodedRnd)
+		if exp, _ := base.Encode(decodedRnd, b); string(res) != string(exp) {
+			t.Errorf("Lookup(%d).Encode got: \n%s \nexpected: \n%s.", b, res, exp)
+		}
+		dec, err := e.Decode(res)
+		if err != nil || string(dec) != string(decodedRnd) {
+			t.Errorf("Lookup(%d).Decode failed: %v.", b, err)
+		}
+	}
+	for _, name := range []string{"", "1", "63", "02", "unknown"} {
+		if _, err := base.Lookup(name); err == nil {
+			t.Errorf("Lookup(%q) succeeded, expected an error.", name)
+		}
+	}
+	for _, name := range base.Names() {
+		if _, err := base.Lookup(name); err != nil {
+			t.Errorf("Lookup(%q) of a registered name failed: %v.", name, err)
+		}
+	}
+}
+
+// registerRuns makes the name registered by TestRegister unique when the test runs more than once.
+var registerRuns int
+
+func TestRegister(t *testing.T) {
+	registerRuns++
+	name := "test-octal-" + strconv.Itoa(registerRuns)
+	a, _ := base.NewAlphabet("01234567")
+	base.Register(name, a)
+	e, err := base.Lookup(name)
+	if err != nil || e != base.Encoding(a) {
+		t.Errorf("Lookup(%s) got: %v %v.", name, e, err)
+	}
+	for _, name := range []string{name, "nix32", "16"} {
+		func() {
+			defer func() {
+				if recover() == nil {
+					t.Errorf("Register(%q) did not panic.", name)
+				}
+			}()
+			base.Register(name, a)
+		}()
+	}
+}
+
+func TestAlphabetEncoding(t *testing.T) {
+	res := base.ShortUUIDAlphabet.Encode(decoded00)
+	if len(res) != 0 {
+		t.Errorf("Alphabet.Encode of zero bytes got: %s expected an empty result.", res)
+	}
+	dec, err := base.ShortUUIDAlphabet.Decode([]byte{})
+	if err != nil || len(dec) != 0 {
+		t.Errorf("Alphabet.Decode of an empty input got: %v %v.", dec, err)
+	}
+	if _, err := base.ShortUUIDAlphabet.Decode([]byte("0")); err == nil {
+		t.Errorf("Alphabet.Decode accepted an illegal character.")
+	}
+}
@@ -0,0 +1,11 @@
+package base
+
+// Internal functions exported for deterministic tests in package base_test.
+var (
+	NanoIDFrom        = nanoID
+	CustomNanoIDFrom  = customNanoID
+	NewCUID2From      = newCUID2
+	CUID2Hash         = cuid2Hash
+	NewUUIDv7At       = newUUIDv7
+	EncodeSecretWiped = encodeSecret
+)
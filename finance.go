@@ -0,0 +1,252 @@
+// Use of this source code is governed by the CC0 1.0
+// license that can be found in the LICENSE file or here:
+// http://creativecommons.org/publicdomain/zero/1.0/
+
+package base
+
+import (
+	"fmt"
+	"strings"
+)
+
+// The financial identifiers IBAN, ISIN, CUSIP and SEDOL all map letters to numbers like base36 digits, A is 10 and Z is 35,
+// before computing their check digits. Their letters are upper case but lower case input is accepted.
+var base36 = mustAlphabet(digits[:36]).CaseInsensitive()
+
+// ibanLengths holds the IBAN length of every country in the IBAN registry.
+var ibanLengths = map[string]int{
+	"AD": 24, "AE": 23, "AL": 28, "AT": 20, "AZ": 28, "BA": 20, "BE": 16, "BG": 22, "BH": 22, "BI": 27,
+	"BR": 29, "BY": 28, "CH": 21, "CR": 22, "CY": 28, "CZ": 24, "DE": 22, "DJ": 27, "DK": 18, "DO": 28,
+	"EE": 20, "EG": 29, "ES": 24, "FI": 18, "FK": 18, "FO": 18, "FR": 27, "GB": 22, "GE": 22, "GI": 23,
+	"GL": 18, "GR": 27, "GT": 28, "HR": 21, "HU": 28, "IE": 22, "IL": 23, "IQ": 23, "IS": 26, "IT": 27,
+	"JO": 30, "KW": 30, "KZ": 20, "LB": 28, "LC": 32, "LI": 21, "LT": 20, "LU": 20, "LV": 21, "LY": 25,
+	"MC": 27, "MD": 24, "ME": 22, "MK": 19, "MN": 20, "MR": 27, "MT": 31, "MU": 30, "NI": 28, "NL": 18,
+	"NO": 15, "OM": 23, "PK": 24, "PL": 28, "PS": 29, "PT": 25, "QA": 29, "RO": 24, "RS": 22, "RU": 33,
+	"SA": 24, "SC": 31, "SD": 18, "SE": 24, "SI": 19, "SK": 24, "SM": 27, "SO": 23, "ST": 25, "SV": 28,
+	"TL": 23, "TN": 24, "TR": 26, "UA": 29, "VA": 22, "VG": 24, "XK": 20, "YE": 30,
+}
+
+// IBANCountryError is returned for an IBAN with a country code missing from the IBAN registry.
+type IBANCountryError string
+
+func (e IBANCountryError) Error() string {
+	return fmt.Sprintf("Unknown IBAN country %q.", string(e))
+}
+
+// IBANLengthError is returned for an IBAN whose length does not match the registered length of its country.
+type IBANLengthError struct {
+	Country  string
+	Length   int
+	Expected int
+}
+
+func (e *IBANLengthError) Error() string {
+	return fmt.Sprintf("Illegal IBAN length %d for country %s, expected %d.", e.Length, e.Country, e.Expected)
+}
+
+// CheckDigitError is returned when the check digits of an identifier do not match its content.
+type CheckDigitError struct {
+	// Kind is the kind of identifier, e.g. "IBAN" or "ISIN".
+	Kind     string
+	Got      string
+	Expected string
+}
+
+func (e *CheckDigitError) Error() string {
+	return fmt.Sprintf("Illegal %s check digits %s, expected %s.", e.Kind, e.Got, e.Expected)
+}
+
+// ValidateIBAN returns nil if s is a valid IBAN in electronic or print format, i.e. with or without spaces.
+//
+// The returned error is an IBANCountryError, an *IBANLengthError or a *CheckDigitError for well formed IBANs that fail those checks.
+func ValidateIBAN(s string) error {
+	s = compactIBAN(s)
+	if len(s) < 4 {
+		return fmt.Errorf("Illegal IBAN length %d.", len(s))
+	}
+	check, err := IBANCheckDigits(s[:2], s[4:])
+	if err != nil {
+		return err
+	}
+	if s[2:4] != check {
+		return &CheckDigitError{Kind: "IBAN", Got: s[2:4], Expected: check}
+	}
+	return nil
+}
+
+// IBANCheckDigits returns the two check digits of the IBAN for the country code country and the national account number bban.
+func IBANCheckDigits(country, bban string) (string, error) {
+	country, bban = strings.ToUpper(country), strings.ToUpper(bban)
+	l, ok := ibanLengths[country]
+	if !ok {
+		return "", IBANCountryError(country)
+	}
+	if len(bban)+4 != l {
+		return "", &IBANLengthError{Country: country, Length: len(bban) + 4, Expected: l}
+	}
+	// Move the country code and zeroed check digits to the end and compute the remainder modulo 97 of
+	// the number formed by replacing every character with its base36 value in decimal.
+	m := 0
+	for _, c := range []byte(bban + country + "00") {
+		v := base36.Index(c)
+		if v < 0 {
+			return "", fmt.Errorf("Illegal character %q in IBAN.", c)
+		}
+		if v >= 10 {
+			m = m * 100
+		} else {
+			m = m * 10
+		}
+		m = (m + v) % 97
+	}
+	return fmt.Sprintf("%02d", 98-m), nil
+}
+
+// NewIBAN returns the IBAN in electronic format for the country code country and the national account number bban.
+func NewIBAN(country, bban string) (string, error) {
+	check, err := IBANCheckDigits(country, bban)
+	if err != nil {
+		return "", err
+	}
+	return strings.ToUpper(country) + check + strings.ToUpper(bban), nil
+}
+
+// FormatIBAN returns the valid IBAN s in print format, upper case and grouped in blocks of four characters.
+func FormatIBAN(s string) (string, error) {
+	if err := ValidateIBAN(s); err != nil {
+		return "", err
+	}
+	s = compactIBAN(s)
+	var b strings.Builder
+	for i := 0; i < len(s); i += 4 {
+		if i > 0 {
+			b.WriteByte(' ')
+		}
+		b.WriteString(s[i:min(i+4, len(s))])
+	}
+	return b.String(), nil
+}
+
+func compactIBAN(s string) string {
+	return strings.ToUpper(strings.ReplaceAll(s, " ", ""))
+}
+
+// ValidateISIN returns nil if s is a valid 12 character International Securities Identification Number.
+func ValidateISIN(s string) error {
+	if len(s) != 12 {
+		return fmt.Errorf("Illegal ISIN length %d.", len(s))
+	}
+	check, err := ISINCheckDigit(s[:11])
+	if err != nil {
+		return err
+	}
+	if base36.Index(s[11]) != int(check-'0') {
+		return &CheckDigitError{Kind: "ISIN", Got: s[11:], Expected: string(check)}
+	}
+	return nil
+}
+
+// ISINCheckDigit returns the check digit for the first 11 characters s of an ISIN, a two letter country code and a nine character security identifier.
+func ISINCheckDigit(s string) (byte, error) {
+	if len(s) != 11 {
+		return 0, fmt.Errorf("Illegal ISIN length %d.", len(s)+1)
+	}
+	if base36.Index(s[0]) < 10 || base36.Index(s[1]) < 10 {
+		return 0, fmt.Errorf("Illegal ISIN country code %q.", s[:2])
+	}
+	// Expand letters to their two digit base36 values and apply the Luhn algorithm.
+	var d []int
+	for _, c := range []byte(s) {
+		v := base36.Index(c)
+		if v < 0 {
+			return 0, fmt.Errorf("Illegal character %q in ISIN.", c)
+		}
+		if v >= 10 {
+			d = append(d, v/10)
+		}
+		d = append(d, v%10)
+	}
+	sum := 0
+	for i := range d {
+		v := d[len(d)-1-i]
+		if i%2 == 0 {
+			v *= 2
+		}
+		sum += v/10 + v%10
+	}
+	return byte('0' + (10-sum%10)%10), nil
+}
+
+// ValidateCUSIP returns nil if s is a valid 9 character CUSIP.
+func ValidateCUSIP(s string) error {
+	if len(s) != 9 {
+		return fmt.Errorf("Illegal CUSIP length %d.", len(s))
+	}
+	check, err := CUSIPCheckDigit(s[:8])
+	if err != nil {
+		return err
+	}
+	if s[8] != check {
+		return &CheckDigitError{Kind: "CUSIP", Got: s[8:], Expected: string(check)}
+	}
+	return nil
+}
+
+// CUSIPCheckDigit returns the check digit for the first 8 characters s of a CUSIP.
+func CUSIPCheckDigit(s string) (byte, error) {
+	if len(s) != 8 {
+		return 0, fmt.Errorf("Illegal CUSIP length %d.", len(s)+1)
+	}
+	sum := 0
+	for i, c := range []byte(s) {
+		v := base36.Index(c)
+		switch c {
+		case '*':
+			v = 36
+		case '@':
+			v = 37
+		case '#':
+			v = 38
+		}
+		if v < 0 {
+			return 0, fmt.Errorf("Illegal character %q in CUSIP.", c)
+		}
+		if i%2 == 1 {
+			v *= 2
+		}
+		sum += v/10 + v%10
+	}
+	return byte('0' + (10-sum%10)%10), nil
+}
+
+// ValidateSEDOL returns nil if s is a valid 7 character Stock Exchange Daily Official List identifier.
+func ValidateSEDOL(s string) error {
+	if len(s) != 7 {
+		return fmt.Errorf("Illegal SEDOL length %d.", len(s))
+	}
+	check, err := SEDOLCheckDigit(s[:6])
+	if err != nil {
+		return err
+	}
+	if s[6] != check {
+		return &CheckDigitError{Kind: "SEDOL", Got: s[6:], Expected: string(check)}
+	}
+	return nil
+}
+
+// SEDOLCheckDigit returns the check digit for the first 6 characters s of a SEDOL. Vowels are not allowed in a SEDOL.
+func SEDOLCheckDigit(s string) (byte, error) {
+	if len(s) != 6 {
+		return 0, fmt.Errorf("Illegal SEDOL length %d.", len(s)+1)
+	}
+	weights := [6]int{1, 3, 1, 7, 3, 9}
+	sum := 0
+	for i, c := range []byte(s) {
+		v := base36.Index(c)
+		if v < 0 || strings.IndexByte("AEIOUaeiou", c) >= 0 {
+			return 0, fmt.Errorf("Illegal character %q in SEDOL.", c)
+		}
+		sum += v * weights[i]
+	}
+	return byte('0' + (10-sum%10)%10), nil
+}
@@ -0,0 +1,108 @@
+package base_test
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/7i/base"
+)
+
+func TestIBAN(t *testing.T) {
+	valid := []string{
+		"GB82WEST12345698765432",
+		"GB82 WEST 1234 5698 7654 32",
+		"de89370400440532013000",
+		"NL91ABNA0417164300",
+		"FR1420041010050500013M02606",
+		"CH9300762011623852957",
+		"NO9386011117947",
+		"BE68539007547034",
+	}
+	for _, s := range valid {
+		if err := base.ValidateIBAN(s); err != nil {
+			t.Errorf("ValidateIBAN(%q) got: %v expected: nil.", s, err)
+		}
+	}
+
+	iban, err := base.NewIBAN("GB", "WEST12345698765432")
+	if err != nil || iban != "GB82WEST12345698765432" {
+		t.Errorf("NewIBAN got: %s %v expected: GB82WEST12345698765432.", iban, err)
+	}
+	f, err := base.FormatIBAN("fr1420041010050500013m02606")
+	if err != nil || f != "FR14 2004 1010 0505 0001 3M02 606" {
+		t.Errorf("FormatIBAN got: %q %v expected: \"FR14 2004 1010 0505 0001 3M02 606\".", f, err)
+	}
+}
+
+func TestIBANErrors(t *testing.T) {
+	var le *base.IBANLengthError
+	err := base.ValidateIBAN("GB82WEST1234569876543")
+	if !errors.As(err, &le) || le.Country != "GB" || le.Length != 21 || le.Expected != 22 {
+		t.Errorf("ValidateIBAN got: %#v expected an *IBANLengthError.", err)
+	}
+	var ce base.IBANCountryError
+	if err := base.ValidateIBAN("XX82WEST12345698765432"); !errors.As(err, &ce) || string(ce) != "XX" {
+		t.Errorf("ValidateIBAN got: %v expected an IBANCountryError.", err)
+	}
+	var de *base.CheckDigitError
+	if err := base.ValidateIBAN("GB83WEST12345698765432"); !errors.As(err, &de) || de.Expected != "82" {
+		t.Errorf("ValidateIBAN got: %v expected a *CheckDigitError.", err)
+	}
+	for _, s := range []string{"", "GB", "GB82WEST1234569876543_"} {
+		if err := base.ValidateIBAN(s); err == nil {
+			t.Errorf("ValidateIBAN(%q) succeeded, expected an error.", s)
+		}
+	}
+}
+
+func TestISIN(t *testing.T) {
+	for _, s := range []string{"US0378331005", "US5949181045", "AU0000XVGZA3", "GB0002634946", "us0378331005"} {
+		if err := base.ValidateISIN(s); err != nil {
+			t.Errorf("ValidateISIN(%q) got: %v expected: nil.", s, err)
+		}
+	}
+	for _, s := range []string{"US0378331006", "US037833100", "1S0378331005", "US03783310-5"} {
+		if err := base.ValidateISIN(s); err == nil {
+			t.Errorf("ValidateISIN(%q) succeeded, expected an error.", s)
+		}
+	}
+}
+
+func TestCUSIP(t *testing.T) {
+	for _, s := range []string{"037833100", "38259P508", "594918104", "68389X105"} {
+		if err := base.ValidateCUSIP(s); err != nil {
+			t.Errorf("ValidateCUSIP(%q) got: %v expected: nil.", s, err)
+		}
+	}
+	for _, s := range []string{"037833101", "03783310", "0378331-0"} {
+		if err := base.ValidateCUSIP(s); err == nil {
+			t.Errorf("ValidateCUSIP(%q) succeeded, expected an error.", s)
+		}
+	}
+	// A US ISIN embeds the CUSIP
+	c, _ := base.ISINCheckDigit("US" + "38259P508")
+	if err := base.ValidateISIN("US38259P508" + string(c)); err != nil {
+		t.Errorf("ValidateISIN of a CUSIP based ISIN got: %v.", err)
+	}
+}
+
+func TestSEDOL(t *testing.T) {
+	tests := map[string]byte{
+		"710889": '9', "B0YBKJ": '7', "406566": '3', "B0YBLH": '2', "228276": '5',
+		"B0YBKL": '9', "557910": '7', "B0YBKR": '5', "585284": '2', "B0YBKT": '7', "B00030": '0',
+	}
+	for s, exp := range tests {
+		c, err := base.SEDOLCheckDigit(s)
+		if err != nil || c != exp {
+			t.Errorf("SEDOLCheckDigit(%q) got: %q %v expected: %q.", s, c, err, exp)
+		}
+		if err := base.ValidateSEDOL(s + string(exp)); err != nil {
+			t.Errorf("ValidateSEDOL(%q) got: %v expected: nil.", s+string(exp), err)
+		}
+	}
+	for _, s := range []string{"0263495", "A263494", "026349"} {
+		if err := base.ValidateSEDOL(s); err == nil {
+			t.Errorf("ValidateSEDOL(%q) succeeded, expected an error.", s)
+		}
+	}
+}
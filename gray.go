@@ -0,0 +1,176 @@
+// Use of this source code is governed by the CC0 1.0
+// license that can be found in the LICENSE file or here:
+// http://creativecommons.org/publicdomain/zero/1.0/
+
+package base
+
+import (
+	"fmt"
+	"iter"
+)
+
+// GrayCode selects the kind of n-ary Gray code used by ToGray, FromGray and GraySequence.
+type GrayCode int
+
+const (
+	// ReflectedGray is the reflected n-ary Gray code, every digit runs up and down like the binary reflected Gray code.
+	// For base 2 it is the ordinary binary Gray code.
+	ReflectedGray GrayCode = iota
+	// ModularGray is the modular n-ary Gray code, every digit is the difference modulo the base between two adjacent digits of the number.
+	// Every step increments exactly one digit modulo the base.
+	ModularGray
+)
+
+// ToGray takes an []byte u containing a base b number and returns []byte r containing its Gray code of kind g with the same number of digits.
+//
+// b can not be grater than 62 or less than 2. If b is over 36 then u is case sensitive.
+//
+// Consecutive numbers of the same width have Gray codes differing in exactly one digit. The wrap around from the largest number back to zero
+// changes one digit too for ModularGray, and for ReflectedGray only with an even base b.
+func ToGray(u []byte, b int, g GrayCode) (r []byte, err error) {
+	a, d, err := grayDigits(u, b)
+	if err != nil {
+		return nil, err
+	}
+	switch g {
+	case ReflectedGray:
+		// The lower digits run backwards whenever the sum of the higher Gray digits is odd.
+		s := 0
+		for i, v := range d {
+			if s%2 == 1 {
+				d[i] = b - 1 - v
+			}
+			s += d[i]
+		}
+	case ModularGray:
+		p := 0
+		for i, v := range d {
+			d[i] = (v - p + b) % b
+			p = v
+		}
+	default:
+		return nil, fmt.Errorf("Illegal Gray code %d.", g)
+	}
+	return grayString(a, d), nil
+}
+
+// FromGray takes an []byte u containing a Gray code of kind g using base b digits and returns []byte r containing the base b number it represents.
+//
+// FromGray is the inverse of ToGray.
+func FromGray(u []byte, b int, g GrayCode) (r []byte, err error) {
+	a, d, err := grayDigits(u, b)
+	if err != nil {
+		return nil, err
+	}
+	switch g {
+	case ReflectedGray:
+		s := 0
+		for i, v := range d {
+			if s%2 == 1 {
+				d[i] = b - 1 - v
+			}
+			s += v
+		}
+	case ModularGray:
+		p := 0
+		for i, v := range d {
+			d[i] = (v + p) % b
+			p = d[i]
+		}
+	default:
+		return nil, fmt.Errorf("Illegal Gray code %d.", g)
+	}
+	return grayString(a, d), nil
+}
+
+// GraySequence returns an iterator over all Gray codes of kind g with width base b digits in sequence order, starting with all zero digits.
+//
+// The yielded slice is reused between iterations, copy it to retain it.
+func GraySequence(b, width int, g GrayCode) (iter.Seq[[]byte], error) {
+	a, err := Digits(b)
+	if err != nil {
+		return nil, err
+	}
+	if width < 1 {
+		return nil, fmt.Errorf("Illegal Gray code width %d.", width)
+	}
+	if g != ReflectedGray && g != ModularGray {
+		return nil, fmt.Errorf("Illegal Gray code %d.", g)
+	}
+	return func(yield func([]byte) bool) {
+		// Walk the sequence by changing a single Gray digit per step, dir
+		// holds the current running direction of every reflected digit.
+		d := make([]int, width)
+		dir := make([]int, width)
+		for i := range dir {
+			dir[i] = 1
+		}
+		r := make([]byte, width)
+		for i := range r {
+			r[i] = a.chars[0]
+		}
+		for {
+			if !yield(r) {
+				return
+			}
+			// Find the lowest digit that is able to move.
+			i := width - 1
+			for ; i >= 0; i-- {
+				if g == ModularGray {
+					d[i] = (d[i] + 1) % b
+					if d[i] != 0 {
+						break
+					}
+					// A wrapped digit keeps its Gray digit, only the digit
+					// receiving the carry changes.
+					continue
+				}
+				if n := d[i] + dir[i]; n >= 0 && n < b {
+					d[i] = n
+					break
+				}
+				dir[i] = -dir[i]
+			}
+			if i < 0 {
+				return
+			}
+			r[i] = a.chars[grayDigit(g, d, i, b)]
+		}
+	}, nil
+}
+
+// grayDigit returns the Gray digit at position i for the kind g given the counter state d.
+func grayDigit(g GrayCode, d []int, i, b int) int {
+	if g == ReflectedGray {
+		return d[i]
+	}
+	// For the modular code d holds the plain digits of the counter.
+	if i == 0 {
+		return d[0]
+	}
+	return (d[i] - d[i-1] + b) % b
+}
+
+func grayDigits(u []byte, b int) (*Alphabet, []int, error) {
+	a, err := Digits(b)
+	if err != nil {
+		return nil, nil, err
+	}
+	d := make([]int, len(u))
+	for i, c := range u {
+		v := a.Index(c)
+		if v < 0 {
+			return nil, nil, fmt.Errorf("Illegal characters in base %d decoding.", b)
+		}
+		d[i] = v
+	}
+	return a, d, nil
+}
+
+func grayString(a *Alphabet, d []int) []byte {
+	r := make([]byte, len(d))
+	for i, v := range d {
+		r[i] = a.chars[v]
+	}
+	return r
+}
@@ -0,0 +1,121 @@
+package base_test
+
+import (
+	"testing"
+
+	"github.com/7i/base"
+)
+
+func TestGrayBinary(t *testing.T) {
+	// Base 2 reflected Gray code is the ordinary binary Gray code n ^ n>>1
+	for n := 0; n < 256; n++ {
+		u := []byte{}
+		g := []byte{}
+		for i := 7; i >= 0; i-- {
+			u = append(u, byte('0'+n>>i&1))
+			g = append(g, byte('0'+(n^n>>1)>>i&1))
+		}
+		res, err := base.ToGray(u, 2, base.ReflectedGray)
+		if err != nil || string(res) != string(g) {
+			t.Errorf("ToGray(%s) got: %s %v expected: %s.", u, res, err, g)
+		}
+	}
+}
+
+func TestGrayKnown(t *testing.T) {
+	tests := []struct {
+		u, g string
+		b    int
+		kind base.GrayCode
+	}{
+		{"00", "00", 3, base.ReflectedGray},
+		{"02", "02", 3, base.ReflectedGray},
+		{"10", "12", 3, base.ReflectedGray},
+		{"12", "10", 3, base.ReflectedGray},
+		{"20", "20", 3, base.ReflectedGray},
+		{"10", "19", 10, base.ReflectedGray},
+		{"19", "10", 10, base.ReflectedGray},
+		{"20", "20", 10, base.ReflectedGray},
+		{"10", "12", 3, base.ModularGray},
+		{"12", "11", 3, base.ModularGray},
+		{"20", "21", 3, base.ModularGray},
+		{"21", "22", 3, base.ModularGray},
+		{"Zz", "z0", 36, base.ReflectedGray},
+	}
+	for _, test := range tests {
+		res, err := base.ToGray([]byte(test.u), test.b, test.kind)
+		if err != nil || string(res) != test.g {
+			t.Errorf("ToGray(%s, %d, %d) got: %s %v expected: %s.", test.u, test.b, test.kind, res, err, test.g)
+		}
+	}
+}
+
+func TestGraySequence(t *testing.T) {
+	for _, kind := range []base.GrayCode{base.ReflectedGray, base.ModularGray} {
+		for _, b := range []int{2, 3, 4, 5, 10, 16} {
+			width := 3
+			a, _ := base.Digits(b)
+			seq, err := base.GraySequence(b, width, kind)
+			if err != nil {
+				t.Fatal(err)
+			}
+			n := 0
+			var first, prev []byte
+			for g := range seq {
+				// The sequence follows ToGray of the counting sequence
+				u := make([]byte, width)
+				for i, v := width-1, n; i >= 0; i, v = i-1, v/b {
+					u[i] = a.String()[v%b]
+				}
+				exp, _ := base.ToGray(u, b, kind)
+				if string(g) != string(exp) {
+					t.Fatalf("GraySequence(%d, %d, %d) step %d got: %s expected: %s.", b, width, kind, n, g, exp)
+				}
+				back, _ := base.FromGray(g, b, kind)
+				if string(back) != string(u) {
+					t.Errorf("FromGray(%s, %d, %d) got: %s expected: %s.", g, b, kind, back, u)
+				}
+				if prev != nil && grayDistance(prev, g) != 1 {
+					t.Errorf("GraySequence(%d, %d, %d) step %d changes more than one digit: %s %s.", b, width, kind, n, prev, g)
+				}
+				if first == nil {
+					first = append([]byte(nil), g...)
+				}
+				prev = append(prev[:0], g...)
+				n++
+			}
+			if n != b*b*b {
+				t.Errorf("GraySequence(%d, %d, %d) yielded %d codes, expected %d.", b, width, kind, n, b*b*b)
+			}
+			// The modular code and the reflected code for even bases are cyclic
+			if (kind == base.ModularGray || b%2 == 0) && grayDistance(prev, first) != 1 {
+				t.Errorf("GraySequence(%d, %d, %d) is not cyclic: %s %s.", b, width, kind, prev, first)
+			}
+		}
+	}
+}
+
+func TestGrayErrors(t *testing.T) {
+	if _, err := base.ToGray([]byte("2"), 2, base.ReflectedGray); err == nil {
+		t.Errorf("ToGray accepted an illegal digit.")
+	}
+	if _, err := base.FromGray([]byte("1"), 63, base.ModularGray); err == nil {
+		t.Errorf("FromGray accepted an illegal base.")
+	}
+	if _, err := base.GraySequence(10, 0, base.ModularGray); err == nil {
+		t.Errorf("GraySequence accepted an illegal width.")
+	}
+	if _, err := base.ToGray([]byte("1"), 2, base.GrayCode(7)); err == nil {
+		t.Errorf("ToGray accepted an illegal Gray code.")
+	}
+}
+
+func grayDistance(a, b []byte) int {
+	n := 0
+	for i := range a {
+		if a[i] != b[i] {
+			n++
+		}
+	}
+	return n
+}
@@ -0,0 +1,111 @@
+// Use of this source code is governed by the CC0 1.0
+// license that can be found in the LICENSE file or here:
+// http://creativecommons.org/publicdomain/zero/1.0/
+
+package base
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"net/http"
+	"sort"
+)
+
+// PathError is returned for a path value of a request that is missing or does not decode.
+//
+// Status is the HTTP status code for the response: http.StatusBadRequest for a missing or empty value and
+// http.StatusNotFound for a value that is not a valid encoding, since no resource can have such an ID.
+type PathError struct {
+	Name   string
+	Value  string
+	Status int
+	Err    error
+}
+
+func (e *PathError) Error() string {
+	return fmt.Sprintf("Illegal path value %s=%q: %v", e.Name, e.Value, e.Err)
+}
+
+func (e *PathError) Unwrap() error {
+	return e.Err
+}
+
+// PathBytes returns the path value name of r, see http.Request.PathValue, decoded with enc.
+// The returned error is a *PathError.
+func PathBytes(r *http.Request, name string, enc Encoding) ([]byte, error) {
+	v := r.PathValue(name)
+	if v == "" {
+		return nil, &PathError{Name: name, Status: http.StatusBadRequest, Err: fmt.Errorf("Missing path value.")}
+	}
+	b, err := enc.Decode([]byte(v))
+	if err != nil {
+		return nil, &PathError{Name: name, Value: v, Status: http.StatusNotFound, Err: err}
+	}
+	return b, nil
+}
+
+// PathUint64 returns the path value name of r decoded with enc as a big endian unsigned integer, like Decode with base 62:
+//
+//	mux.HandleFunc("GET /users/{id}", func(w http.ResponseWriter, r *http.Request) {
+//		id, err := base.PathUint64(r, "id", base62)
+//		if err != nil {
+//			base.WritePathError(w, err)
+//			return
+//		}
+//		...
+//	})
+//
+// The returned error is a *PathError, values that do not fit in an uint64 are not found.
+func PathUint64(r *http.Request, name string, enc Encoding) (uint64, error) {
+	b, err := PathBytes(r, name, enc)
+	if err != nil {
+		return 0, err
+	}
+	b = bytes.TrimLeft(b, "\x00")
+	if len(b) > 8 {
+		return 0, &PathError{Name: name, Value: r.PathValue(name), Status: http.StatusNotFound, Err: fmt.Errorf("Path value overflows uint64.")}
+	}
+	var x uint64
+	for _, c := range b {
+		x = x<<8 | uint64(c)
+	}
+	return x, nil
+}
+
+// WritePathError replies to the request with the status of err if it is a *PathError and with http.StatusInternalServerError otherwise.
+// The body is the status text only, the rejected value is not echoed.
+func WritePathError(w http.ResponseWriter, err error) {
+	status := http.StatusInternalServerError
+	var pe *PathError
+	if errors.As(err, &pe) {
+		status = pe.Status
+	}
+	http.Error(w, http.StatusText(status), status)
+}
+
+// ValidatePath returns middleware that checks that every path value named in encs decodes with its encoding before calling the next handler.
+// Requests with an invalid value get the response of WritePathError, values are checked in name order.
+//
+//	mux.Handle("GET /users/{id}/keys/{key}", base.ValidatePath(map[string]base.Encoding{
+//		"id":  base62,
+//		"key": base.Base58BTC,
+//	})(handler))
+func ValidatePath(encs map[string]Encoding) func(http.Handler) http.Handler {
+	names := make([]string, 0, len(encs))
+	for name := range encs {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			for _, name := range names {
+				if _, err := PathBytes(r, name, encs[name]); err != nil {
+					WritePathError(w, err)
+					return
+				}
+			}
+			next.ServeHTTP(w, r)
+		})
+	}
+}
@@ -0,0 +1,89 @@
+package base_test
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/7i/base"
+)
+
+func TestPathUint64(t *testing.T) {
+	b62, _ := base.Digits(62)
+	handler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		id, err := base.PathUint64(r, "id", b62)
+		if err != nil {
+			base.WritePathError(w, err)
+			return
+		}
+		fmt.Fprint(w, id)
+	})
+
+	// The path values are set like a ServeMux pattern GET /users/{id} would.
+	tests := []struct {
+		id     string
+		status int
+		body   string
+	}{
+		{"100", http.StatusOK, "3844"},
+		{"lYGhA16ahyf", http.StatusOK, "18446744073709551615"},
+		{"lYGhA16ahyg", http.StatusNotFound, "Not Found\n"}, // 2^64
+		{"a-b", http.StatusNotFound, "Not Found\n"},
+		{"", http.StatusBadRequest, "Bad Request\n"},
+	}
+	for _, test := range tests {
+		r := httptest.NewRequest("GET", "/users/"+test.id, nil)
+		if test.id != "" {
+			r.SetPathValue("id", test.id)
+		}
+		w := httptest.NewRecorder()
+		handler.ServeHTTP(w, r)
+		if w.Code != test.status || w.Body.String() != test.body {
+			t.Errorf("GET /users/%s got: %d %q expected: %d %q.", test.id, w.Code, w.Body.String(), test.status, test.body)
+		}
+	}
+}
+
+func TestValidatePath(t *testing.T) {
+	b62, _ := base.Digits(62)
+	validate := base.ValidatePath(map[string]base.Encoding{"id": b62, "key": base.Base58BTC})
+	handler := validate(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		key, err := base.PathBytes(r, "key", base.Base58BTC)
+		fmt.Fprintf(w, "%s %x %v", r.PathValue("id"), key, err)
+	}))
+
+	// The path values are set like a ServeMux pattern GET /users/{id}/keys/{key} would.
+	tests := []struct {
+		id, key string
+		status  int
+		body    string
+	}{
+		{"Zz", "1112", http.StatusOK, "Zz 00000001 <nil>"},
+		{"Z_z", "1112", http.StatusNotFound, "Not Found\n"},
+		{"Zz", "0OIl", http.StatusNotFound, "Not Found\n"},
+	}
+	for _, test := range tests {
+		r := httptest.NewRequest("GET", "/users/"+test.id+"/keys/"+test.key, nil)
+		r.SetPathValue("id", test.id)
+		r.SetPathValue("key", test.key)
+		w := httptest.NewRecorder()
+		handler.ServeHTTP(w, r)
+		if w.Code != test.status || w.Body.String() != test.body {
+			t.Errorf("GET /users/%s/keys/%s got: %d %q expected: %d %q.", test.id, test.key, w.Code, w.Body.String(), test.status, test.body)
+		}
+	}
+
+	// Without a matching wildcard the value is missing.
+	_, err := base.PathBytes(httptest.NewRequest("GET", "/", nil), "id", b62)
+	var pe *base.PathError
+	if !errors.As(err, &pe) || pe.Status != http.StatusBadRequest || pe.Name != "id" {
+		t.Errorf("PathBytes without path values got: %v.", err)
+	}
+	w := httptest.NewRecorder()
+	base.WritePathError(w, errors.New("other"))
+	if w.Code != http.StatusInternalServerError {
+		t.Errorf("WritePathError of another error got: %d.", w.Code)
+	}
+}
@@ -0,0 +1,687 @@
+// Use of this source code is governed by the CC0 1.0
+// license that can be found in the LICENSE file or here:
+// http://creativecommons.org/publicdomain/zero/1.0/
+
+package base
+
+import (
+	"bytes"
+	"encoding"
+	"encoding/json"
+	"fmt"
+	"io"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// MarshalJSON returns the JSON encoding of v like json.Marshal, except that struct fields with a base tag
+// are encoded as JSON strings using the encoding named by the tag, see Lookup:
+//
+//	type User struct {
+//		ID  uint64 `json:"id" base:"62"`
+//		Key []byte `json:"key,omitempty" base:"58btc"`
+//	}
+//
+// Tagged fields may be []byte, byte arrays, unsigned or non negative signed integers, or pointers to those.
+// Integers are encoded as their minimal big endian bytes, so zero is the empty encoding for positional encodings.
+// Fields without a base tag are encoded by json.Marshal with their json tag, so every option of it is honored.
+// Fields with a base tag honor the options omitempty and omitzero, the string option has no effect as their values are strings already.
+// Values whose types have no base tags, and types implementing json.Marshaler, are encoded by json.Marshal.
+// Map keys may be strings, integers or implement encoding.TextMarshaler like for json.Marshal.
+func MarshalJSON(v any) ([]byte, error) {
+	var buf bytes.Buffer
+	if err := marshalJSON(&buf, reflect.ValueOf(v)); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// UnmarshalJSON parses the JSON encoded data into the value pointed to by v like json.Unmarshal,
+// decoding the strings of struct fields with a base tag like MarshalJSON encodes them.
+func UnmarshalJSON(data []byte, v any) error {
+	rv := reflect.ValueOf(v)
+	if rv.Kind() != reflect.Pointer || rv.IsNil() {
+		return &json.InvalidUnmarshalError{Type: reflect.TypeOf(v)}
+	}
+	return unmarshalJSON(data, rv.Elem())
+}
+
+var (
+	marshalerType     = reflect.TypeFor[json.Marshaler]()
+	unmarshalerType   = reflect.TypeFor[json.Unmarshaler]()
+	textMarshalType   = reflect.TypeFor[encoding.TextMarshaler]()
+	textUnmarshalType = reflect.TypeFor[encoding.TextUnmarshaler]()
+	isZeroerType      = reflect.TypeFor[interface{ IsZero() bool }]()
+)
+
+// jsonField is an exported struct field as seen by encoding/json.
+type jsonField struct {
+	name                string
+	index               []int
+	omitEmpty, omitZero bool
+	// enc is the encoding of a field with a base tag, nil otherwise.
+	enc Encoding
+	// wrap is a struct type with a single field of the type and json tag of a field that is left to encoding/json,
+	// nil for fields that may contain base tags.
+	wrap reflect.Type
+}
+
+var jsonCache sync.Map // reflect.Type to *jsonType
+
+type jsonType struct {
+	fields []jsonField
+	err    error
+}
+
+var taggedCache sync.Map // reflect.Type to bool
+
+func marshalJSON(buf *bytes.Buffer, v reflect.Value) error {
+	if v.IsValid() && v.Kind() == reflect.Interface && !v.IsNil() {
+		return marshalJSON(buf, v.Elem())
+	}
+	if !v.IsValid() || !jsonTagged(v.Type()) || v.Type().Implements(marshalerType) || v.Type().Implements(textMarshalType) {
+		b, err := json.Marshal(jsonInterface(v))
+		buf.Write(b)
+		return err
+	}
+	switch v.Kind() {
+	case reflect.Pointer:
+		if v.IsNil() {
+			buf.WriteString("null")
+			return nil
+		}
+		return marshalJSON(buf, v.Elem())
+	case reflect.Slice, reflect.Array:
+		if v.Kind() == reflect.Slice && v.IsNil() {
+			buf.WriteString("null")
+			return nil
+		}
+		buf.WriteByte('[')
+		for i := 0; i < v.Len(); i++ {
+			if i > 0 {
+				buf.WriteByte(',')
+			}
+			if err := marshalJSON(buf, v.Index(i)); err != nil {
+				return err
+			}
+		}
+		buf.WriteByte(']')
+		return nil
+	case reflect.Map:
+		if v.IsNil() {
+			buf.WriteString("null")
+			return nil
+		}
+		type entry struct {
+			key string
+			v   reflect.Value
+		}
+		var entries []entry
+		for it := v.MapRange(); it.Next(); {
+			key, err := jsonMapKey(it.Key())
+			if err != nil {
+				return err
+			}
+			entries = append(entries, entry{key, it.Value()})
+		}
+		sort.Slice(entries, func(i, j int) bool { return entries[i].key < entries[j].key })
+		buf.WriteByte('{')
+		for i, e := range entries {
+			if i > 0 {
+				buf.WriteByte(',')
+			}
+			name, _ := json.Marshal(e.key)
+			buf.Write(name)
+			buf.WriteByte(':')
+			if err := marshalJSON(buf, e.v); err != nil {
+				return err
+			}
+		}
+		buf.WriteByte('}')
+		return nil
+	}
+
+	// Structs
+	jt := jsonStruct(v.Type())
+	if jt.err != nil {
+		return jt.err
+	}
+	buf.WriteByte('{')
+	first := true
+	for _, f := range jt.fields {
+		fv, ok := fieldByIndex(v, f.index, false)
+		if !ok || f.omitEmpty && jsonEmpty(fv) || f.omitZero && jsonZero(fv) {
+			continue
+		}
+		var value []byte
+		if f.wrap != nil {
+			w := reflect.New(f.wrap).Elem()
+			w.Field(0).Set(fv)
+			b, err := json.Marshal(w.Interface())
+			if err != nil {
+				return fmt.Errorf("Field %s: %v", f.name, err)
+			}
+			// b is {"v":value} or {} if the value is omitted.
+			if len(b) == 2 {
+				continue
+			}
+			value = b[len(`{"v":`) : len(b)-1]
+		}
+		if !first {
+			buf.WriteByte(',')
+		}
+		first = false
+		name, _ := json.Marshal(f.name)
+		buf.Write(name)
+		buf.WriteByte(':')
+		var err error
+		switch {
+		case value != nil:
+			buf.Write(value)
+		case f.enc != nil:
+			err = marshalTagged(buf, fv, f.enc)
+		default:
+			err = marshalJSON(buf, fv)
+		}
+		if err != nil {
+			return fmt.Errorf("Field %s: %v", f.name, err)
+		}
+	}
+	buf.WriteByte('}')
+	return nil
+}
+
+// marshalTagged writes the value v of a field with a base tag as a JSON string encoded with enc.
+func marshalTagged(buf *bytes.Buffer, v reflect.Value, enc Encoding) error {
+	if v.Kind() == reflect.Pointer {
+		if v.IsNil() {
+			buf.WriteString("null")
+			return nil
+		}
+		v = v.Elem()
+	}
+	var b []byte
+	switch {
+	case v.Kind() == reflect.Slice && v.Type().Elem().Kind() == reflect.Uint8:
+		if v.IsNil() {
+			buf.WriteString("null")
+			return nil
+		}
+		b = v.Bytes()
+	case v.Kind() == reflect.Array && v.Type().Elem().Kind() == reflect.Uint8:
+		b = make([]byte, v.Len())
+		reflect.Copy(reflect.ValueOf(b), v)
+	case v.CanUint():
+		b = uintBytes(v.Uint())
+	case v.CanInt():
+		if v.Int() < 0 {
+			return fmt.Errorf("Illegal negative integer %d for base tag.", v.Int())
+		}
+		b = uintBytes(uint64(v.Int()))
+	default:
+		return fmt.Errorf("Unsupported type %s for base tag.", v.Type())
+	}
+	s, _ := json.Marshal(string(enc.Encode(b)))
+	buf.Write(s)
+	return nil
+}
+
+func unmarshalJSON(data []byte, v reflect.Value) error {
+	if !jsonTagged(v.Type()) || v.Addr().Type().Implements(unmarshalerType) || v.Kind() == reflect.Interface {
+		return json.Unmarshal(data, v.Addr().Interface())
+	}
+	data = bytes.TrimSpace(data)
+	if string(data) == "null" {
+		// Like encoding/json null only resets pointers, slices and maps.
+		switch v.Kind() {
+		case reflect.Pointer, reflect.Slice, reflect.Map:
+			v.SetZero()
+		}
+		return nil
+	}
+	switch v.Kind() {
+	case reflect.Pointer:
+		if v.IsNil() {
+			v.Set(reflect.New(v.Type().Elem()))
+		}
+		return unmarshalJSON(data, v.Elem())
+	case reflect.Slice, reflect.Array:
+		var elems []json.RawMessage
+		if err := json.Unmarshal(data, &elems); err != nil {
+			return err
+		}
+		if v.Kind() == reflect.Slice {
+			v.Set(reflect.MakeSlice(v.Type(), len(elems), len(elems)))
+		}
+		for i := 0; i < v.Len(); i++ {
+			if i >= len(elems) {
+				v.Index(i).SetZero()
+				continue
+			}
+			if err := unmarshalJSON(elems[i], v.Index(i)); err != nil {
+				return err
+			}
+		}
+		return nil
+	case reflect.Map:
+		var elems map[string]json.RawMessage
+		if err := json.Unmarshal(data, &elems); err != nil {
+			return err
+		}
+		if v.IsNil() {
+			v.Set(reflect.MakeMapWithSize(v.Type(), len(elems)))
+		}
+		for k, raw := range elems {
+			key, err := jsonParseMapKey(k, v.Type().Key())
+			if err != nil {
+				return err
+			}
+			e := reflect.New(v.Type().Elem()).Elem()
+			if err := unmarshalJSON(raw, e); err != nil {
+				return err
+			}
+			v.SetMapIndex(key, e)
+		}
+		return nil
+	}
+
+	// Structs
+	jt := jsonStruct(v.Type())
+	if jt.err != nil {
+		return jt.err
+	}
+	members, err := jsonMembers(data)
+	if err != nil {
+		return err
+	}
+	// Members are decoded in document order, so of duplicate keys the last one wins like for encoding/json.
+	for _, m := range members {
+		f := jt.field(m.key)
+		if f == nil {
+			continue
+		}
+		raw := m.raw
+		fv, ok := fieldByIndex(v, f.index, true)
+		if !ok {
+			return fmt.Errorf("Field %s: cannot set embedded pointer to unexported struct.", f.name)
+		}
+		switch {
+		case f.wrap != nil:
+			// The current value is kept for merging like encoding/json does.
+			w := reflect.New(f.wrap)
+			w.Elem().Field(0).Set(fv)
+			if err = json.Unmarshal(append(append([]byte(`{"v":`), raw...), '}'), w.Interface()); err == nil {
+				fv.Set(w.Elem().Field(0))
+			}
+		case f.enc != nil:
+			err = unmarshalTagged(raw, fv, f.enc)
+		default:
+			err = unmarshalJSON(raw, fv)
+		}
+		if err != nil {
+			return fmt.Errorf("Field %s: %v", f.name, err)
+		}
+	}
+	return nil
+}
+
+type jsonMember struct {
+	key string
+	raw json.RawMessage
+}
+
+// jsonMembers returns the members of the JSON object data in document order.
+func jsonMembers(data []byte) ([]jsonMember, error) {
+	dec := json.NewDecoder(bytes.NewReader(data))
+	if t, err := dec.Token(); err != nil || t != json.Delim('{') {
+		// Report the error of encoding/json for anything but an object.
+		var m map[string]json.RawMessage
+		if err := json.Unmarshal(data, &m); err != nil {
+			return nil, err
+		}
+		return nil, fmt.Errorf("Illegal JSON object.")
+	}
+	var members []jsonMember
+	for dec.More() {
+		t, err := dec.Token()
+		if err != nil {
+			return nil, err
+		}
+		m := jsonMember{key: t.(string)}
+		if err := dec.Decode(&m.raw); err != nil {
+			return nil, err
+		}
+		members = append(members, m)
+	}
+	if _, err := dec.Token(); err != nil {
+		return nil, err
+	}
+	if _, err := dec.Token(); err != io.EOF {
+		return nil, fmt.Errorf("Illegal data after JSON object.")
+	}
+	return members, nil
+}
+
+// jsonMapKey returns the JSON object key of the map key k like encoding/json.
+func jsonMapKey(k reflect.Value) (string, error) {
+	if k.Kind() == reflect.String {
+		return k.String(), nil
+	}
+	if k.Type().Implements(textMarshalType) {
+		if k.Kind() == reflect.Pointer && k.IsNil() {
+			return "", nil
+		}
+		b, err := k.Interface().(encoding.TextMarshaler).MarshalText()
+		return string(b), err
+	}
+	switch {
+	case k.CanInt():
+		return strconv.FormatInt(k.Int(), 10), nil
+	case k.CanUint():
+		return strconv.FormatUint(k.Uint(), 10), nil
+	}
+	return "", fmt.Errorf("Unsupported map key type %s.", k.Type())
+}
+
+// jsonParseMapKey returns the map key of type t for the JSON object key s like encoding/json.
+func jsonParseMapKey(s string, t reflect.Type) (reflect.Value, error) {
+	if reflect.PointerTo(t).Implements(textUnmarshalType) {
+		k := reflect.New(t)
+		if err := k.Interface().(encoding.TextUnmarshaler).UnmarshalText([]byte(s)); err != nil {
+			return reflect.Value{}, err
+		}
+		return k.Elem(), nil
+	}
+	k := reflect.New(t).Elem()
+	switch {
+	case t.Kind() == reflect.String:
+		k.SetString(s)
+	case k.CanInt():
+		n, err := strconv.ParseInt(s, 10, 64)
+		if err != nil || k.OverflowInt(n) {
+			return reflect.Value{}, fmt.Errorf("Illegal map key %q for %s.", s, t)
+		}
+		k.SetInt(n)
+	case k.CanUint():
+		n, err := strconv.ParseUint(s, 10, 64)
+		if err != nil || k.OverflowUint(n) {
+			return reflect.Value{}, fmt.Errorf("Illegal map key %q for %s.", s, t)
+		}
+		k.SetUint(n)
+	default:
+		return reflect.Value{}, fmt.Errorf("Unsupported map key type %s.", t)
+	}
+	return k, nil
+}
+
+// unmarshalTagged decodes the JSON string raw with enc and stores the result in the value v of a field with a base tag.
+func unmarshalTagged(raw json.RawMessage, v reflect.Value, enc Encoding) error {
+	var s *string
+	if err := json.Unmarshal(raw, &s); err != nil {
+		return err
+	}
+	if s == nil {
+		switch v.Kind() {
+		case reflect.Pointer, reflect.Slice:
+			v.SetZero()
+		}
+		return nil
+	}
+	if v.Kind() == reflect.Pointer {
+		if v.IsNil() {
+			v.Set(reflect.New(v.Type().Elem()))
+		}
+		v = v.Elem()
+	}
+	b, err := enc.Decode([]byte(*s))
+	if err != nil {
+		return err
+	}
+	switch {
+	case v.Kind() == reflect.Slice && v.Type().Elem().Kind() == reflect.Uint8:
+		v.SetBytes(append([]byte{}, b...))
+		return nil
+	case v.Kind() == reflect.Array && v.Type().Elem().Kind() == reflect.Uint8:
+		// Positional encodings drop leading zero bytes, the data is right aligned.
+		if len(b) > v.Len() {
+			return fmt.Errorf("Decoded %d bytes do not fit in %s.", len(b), v.Type())
+		}
+		v.SetZero()
+		reflect.Copy(v.Slice(v.Len()-len(b), v.Len()), reflect.ValueOf(b))
+		return nil
+	}
+	b = bytes.TrimLeft(b, "\x00")
+	if len(b) > 8 {
+		return fmt.Errorf("Decoded integer does not fit in %s.", v.Type())
+	}
+	var x uint64
+	for _, c := range b {
+		x = x<<8 | uint64(c)
+	}
+	switch {
+	case v.CanUint() && !v.OverflowUint(x):
+		v.SetUint(x)
+	case v.CanInt() && x <= 1<<63-1 && !v.OverflowInt(int64(x)):
+		v.SetInt(int64(x))
+	case v.CanUint() || v.CanInt():
+		return fmt.Errorf("Decoded integer %d does not fit in %s.", x, v.Type())
+	default:
+		return fmt.Errorf("Unsupported type %s for base tag.", v.Type())
+	}
+	return nil
+}
+
+// field returns the field for the JSON object key, preferring an exact match over a case insensitive match like encoding/json.
+func (jt *jsonType) field(key string) *jsonField {
+	var fold *jsonField
+	for i := range jt.fields {
+		f := &jt.fields[i]
+		if f.name == key {
+			return f
+		}
+		if fold == nil && strings.EqualFold(f.name, key) {
+			fold = f
+		}
+	}
+	return fold
+}
+
+// jsonStruct returns the cached fields of the struct type t.
+func jsonStruct(t reflect.Type) *jsonType {
+	if jt, ok := jsonCache.Load(t); ok {
+		return jt.(*jsonType)
+	}
+	jt := &jsonType{}
+	jt.fields, jt.err = structFields(t)
+	jsonCache.Store(t, jt)
+	return jt
+}
+
+// structFields returns the fields of the struct type t visible to encoding/json, including promoted fields of embedded structs.
+// Of several fields with the same name the least nested one wins, ties are broken by a json tag or drop all fields like encoding/json.
+func structFields(t reflect.Type) ([]jsonField, error) {
+	type candidate struct {
+		jsonField
+		depth  int
+		named  bool
+		hidden bool
+	}
+	var all []candidate
+	var walk func(t reflect.Type, index []int) error
+	walk = func(t reflect.Type, index []int) error {
+		for i := 0; i < t.NumField(); i++ {
+			sf := t.Field(i)
+			tag := sf.Tag.Get("json")
+			if tag == "-" {
+				continue
+			}
+			name, opts, _ := strings.Cut(tag, ",")
+			idx := append(append([]int(nil), index...), i)
+			ft := sf.Type
+			if ft.Kind() == reflect.Pointer {
+				ft = ft.Elem()
+			}
+			// Like encoding/json the exported fields of unexported embedded structs are promoted.
+			if sf.Anonymous && name == "" && ft.Kind() == reflect.Struct && sf.Tag.Get("base") == "" {
+				if err := walk(ft, idx); err != nil {
+					return err
+				}
+				continue
+			}
+			if !sf.IsExported() {
+				continue
+			}
+			f := candidate{jsonField: jsonField{name: sf.Name, index: idx}, depth: len(idx), named: name != ""}
+			if name != "" {
+				f.name = name
+			}
+			for _, o := range strings.Split(opts, ",") {
+				switch o {
+				case "omitempty":
+					f.omitEmpty = true
+				case "omitzero":
+					f.omitZero = true
+				}
+			}
+			if b := sf.Tag.Get("base"); b != "" {
+				e, err := Lookup(b)
+				if err != nil {
+					return fmt.Errorf("Field %s of %s: %v", sf.Name, t, err)
+				}
+				f.enc = e
+			} else if sf.Type.Kind() != reflect.Interface && !jsonTagged(sf.Type) {
+				// encoding/json handles the field with all options of its tag.
+				f.wrap = reflect.StructOf([]reflect.StructField{{
+					Name: "V",
+					Type: sf.Type,
+					Tag:  reflect.StructTag(`json:"v,` + opts + `"`),
+				}})
+				f.omitEmpty, f.omitZero = false, false
+			}
+			all = append(all, f)
+		}
+		return nil
+	}
+	if err := walk(t, nil); err != nil {
+		return nil, err
+	}
+
+	// Resolve duplicate names keeping the field order of the struct.
+	for i := range all {
+		for j := range all {
+			if i == j || all[i].name != all[j].name {
+				continue
+			}
+			a, b := all[i], all[j]
+			if a.depth > b.depth || a.depth == b.depth && (!a.named || b.named) {
+				all[i].hidden = true
+			}
+		}
+	}
+	var fields []jsonField
+	for _, f := range all {
+		if !f.hidden {
+			fields = append(fields, f.jsonField)
+		}
+	}
+	return fields, nil
+}
+
+// jsonTagged reports whether values of type t need the reflection walk of MarshalJSON, i.e. whether t contains a struct field with a base tag.
+func jsonTagged(t reflect.Type) bool {
+	if tagged, ok := taggedCache.Load(t); ok {
+		return tagged.(bool)
+	}
+	tagged := typeTagged(t, map[reflect.Type]bool{})
+	taggedCache.Store(t, tagged)
+	return tagged
+}
+
+func typeTagged(t reflect.Type, seen map[reflect.Type]bool) bool {
+	if seen[t] {
+		return false
+	}
+	seen[t] = true
+	switch t.Kind() {
+	case reflect.Pointer, reflect.Slice, reflect.Array, reflect.Map:
+		return typeTagged(t.Elem(), seen)
+	case reflect.Struct:
+		for i := 0; i < t.NumField(); i++ {
+			sf := t.Field(i)
+			if sf.Tag.Get("base") != "" || typeTagged(sf.Type, seen) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// fieldByIndex returns the field of the struct v at index, allocating nil embedded struct pointers if alloc is true.
+// It returns false if a nil embedded pointer hides the field, and can not be allocated if it is unexported.
+func fieldByIndex(v reflect.Value, index []int, alloc bool) (reflect.Value, bool) {
+	for i, x := range index {
+		if i > 0 && v.Kind() == reflect.Pointer {
+			if v.IsNil() {
+				if !alloc || !v.CanSet() {
+					return reflect.Value{}, false
+				}
+				v.Set(reflect.New(v.Type().Elem()))
+			}
+			v = v.Elem()
+		}
+		v = v.Field(x)
+	}
+	return v, true
+}
+
+// jsonEmpty reports whether v is empty in the sense of the omitempty option of encoding/json.
+func jsonEmpty(v reflect.Value) bool {
+	switch v.Kind() {
+	case reflect.Array, reflect.Map, reflect.Slice, reflect.String:
+		return v.Len() == 0
+	case reflect.Bool, reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr,
+		reflect.Float32, reflect.Float64:
+		return v.IsZero()
+	case reflect.Interface, reflect.Pointer:
+		return v.IsNil()
+	}
+	return false
+}
+
+// jsonZero reports whether v is zero in the sense of the omitzero option of encoding/json, using an IsZero method if there is one.
+func jsonZero(v reflect.Value) bool {
+	t := v.Type()
+	switch {
+	case t.Implements(isZeroerType):
+		if (t.Kind() == reflect.Pointer || t.Kind() == reflect.Interface) && v.IsNil() {
+			return true
+		}
+		return v.Interface().(interface{ IsZero() bool }).IsZero()
+	case reflect.PointerTo(t).Implements(isZeroerType):
+		p := reflect.New(t)
+		p.Elem().Set(v)
+		return p.Interface().(interface{ IsZero() bool }).IsZero()
+	}
+	return v.IsZero()
+}
+
+// jsonInterface returns v as an interface value for json.Marshal, nil for the zero Value.
+func jsonInterface(v reflect.Value) any {
+	if !v.IsValid() {
+		return nil
+	}
+	return v.Interface()
+}
+
+// uintBytes returns the minimal big endian bytes of x, no bytes for zero.
+func uintBytes(x uint64) []byte {
+	var b []byte
+	for ; x > 0; x >>= 8 {
+		b = append([]byte{byte(x)}, b...)
+	}
+	return b
+}
@@ -0,0 +1,215 @@
+package base_test
+
+import (
+	"encoding/json"
+	"net/netip"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/7i/base"
+)
+
+type jsonKey struct {
+	Key []byte `base:"58btc"`
+}
+
+// JSONKey is exported, encoding/json ignores embedded pointers to unexported struct types.
+type JSONKey jsonKey
+
+type jsonUser struct {
+	ID     uint64  `json:"id" base:"62"`
+	Key    []byte  `json:"key,omitempty" base:"58btc"`
+	Hash   [4]byte `json:"hash" base:"16"`
+	Ref    *int32  `json:"ref" base:"36"`
+	Name   string  `json:"name"`
+	Skip   int     `json:"-" base:"62"`
+	Tags   []string
+	Keys   []jsonKey          `json:"keys,omitempty"`
+	ByName map[string]jsonKey `json:"by_name,omitempty"`
+	*JSONKey
+}
+
+func TestMarshalJSON(t *testing.T) {
+	ref := int32(1295)
+	u := jsonUser{
+		ID:     3844,
+		Key:    []byte{0, 0, 1},
+		Hash:   [4]byte{0, 0, 0xab, 0xcd},
+		Ref:    &ref,
+		Name:   "<x>",
+		Skip:   7,
+		Keys:   []jsonKey{{[]byte{0xff}}},
+		ByName: map[string]jsonKey{"b": {nil}, "a": {[]byte{1}}},
+	}
+	exp := `{"id":"100","key":"112","hash":"abcd","ref":"zz","name":"\u003cx\u003e","Tags":null,"keys":[{"Key":"5Q"}],"by_name":{"a":{"Key":"2"},"b":{"Key":null}}}`
+	b, err := base.MarshalJSON(u)
+	if err != nil || string(b) != exp {
+		t.Fatalf("MarshalJSON got: %s %v expected: %s.", b, err, exp)
+	}
+	var back jsonUser
+	if err := base.UnmarshalJSON(b, &back); err != nil {
+		t.Fatalf("UnmarshalJSON(%s) got error: %v.", b, err)
+	}
+	u.Skip = 0
+	if !reflect.DeepEqual(back, u) {
+		t.Errorf("UnmarshalJSON(%s) got: %+v expected: %+v.", b, back, u)
+	}
+
+	// The embedded struct pointer promotes its field, omitempty drops the empty key.
+	u = jsonUser{JSONKey: &JSONKey{[]byte("hi")}, Key: []byte{}}
+	exp = `{"id":"","hash":"","ref":null,"name":"","Tags":null,"Key":"8wr"}`
+	if b, err = base.MarshalJSON(&u); err != nil || string(b) != exp {
+		t.Errorf("MarshalJSON got: %s %v expected: %s.", b, err, exp)
+	}
+	back = jsonUser{}
+	if err := base.UnmarshalJSON(b, &back); err != nil || back.JSONKey == nil || string(back.JSONKey.Key) != "hi" {
+		t.Errorf("UnmarshalJSON(%s) got: %+v %v.", b, back, err)
+	}
+
+	// Values without base tags are handled by encoding/json.
+	plain := map[string][]int{"a": {1, 2}}
+	b, err = base.MarshalJSON(plain)
+	if std, _ := json.Marshal(plain); err != nil || string(b) != string(std) {
+		t.Errorf("MarshalJSON(%v) got: %s %v expected: %s.", plain, b, err, std)
+	}
+}
+
+func TestMarshalJSONErrors(t *testing.T) {
+	if _, err := base.MarshalJSON(struct {
+		N int `base:"62"`
+	}{-1}); err == nil {
+		t.Errorf("MarshalJSON accepted a negative integer.")
+	}
+	if _, err := base.MarshalJSON(struct {
+		F float64 `base:"62"`
+	}{1}); err == nil {
+		t.Errorf("MarshalJSON accepted a float field.")
+	}
+	if _, err := base.MarshalJSON(struct {
+		B []byte `base:"unknown"`
+	}{}); err == nil {
+		t.Errorf("MarshalJSON accepted an unknown encoding.")
+	}
+
+	var small struct {
+		N uint8   `base:"16"`
+		A [1]byte `base:"16"`
+		K []byte  `base:"58btc"`
+	}
+	for _, s := range []string{`{"N":"100"}`, `{"A":"100"}`, `{"K":"0"}`, `{"K":1}`, `[1]`} {
+		if err := base.UnmarshalJSON([]byte(s), &small); err == nil {
+			t.Errorf("UnmarshalJSON(%s) succeeded, expected an error.", s)
+		}
+	}
+	if err := base.UnmarshalJSON([]byte(`{}`), small); err == nil {
+		t.Errorf("UnmarshalJSON accepted a non pointer.")
+	}
+}
+
+type jsonOptions struct {
+	ID      uint64                 `json:"id" base:"62"`
+	Count   int64                  `json:"count,string"`
+	Flag    *bool                  `json:"flag,string,omitempty"`
+	Created time.Time              `json:"created,omitzero"`
+	Hash    [4]byte                `json:"hash,omitzero" base:"16"`
+	ByID    map[int]jsonKey        `json:"by_id,omitempty"`
+	ByAddr  map[netip.Addr]jsonKey `json:"by_addr,omitempty"`
+}
+
+func TestMarshalJSONOptions(t *testing.T) {
+	yes := true
+	v := jsonOptions{
+		ID:     62,
+		Count:  7,
+		Flag:   &yes,
+		ByID:   map[int]jsonKey{10: {[]byte{1}}, -2: {nil}},
+		ByAddr: map[netip.Addr]jsonKey{netip.MustParseAddr("::1"): {[]byte{2}}},
+	}
+	exp := `{"id":"10","count":"7","flag":"true","by_id":{"-2":{"Key":null},"10":{"Key":"2"}},"by_addr":{"::1":{"Key":"3"}}}`
+	b, err := base.MarshalJSON(v)
+	if err != nil || string(b) != exp {
+		t.Fatalf("MarshalJSON got: %s %v expected: %s.", b, err, exp)
+	}
+	var back jsonOptions
+	if err := base.UnmarshalJSON(b, &back); err != nil || !reflect.DeepEqual(back, v) {
+		t.Errorf("UnmarshalJSON(%s) got: %+v %v expected: %+v.", b, back, err, v)
+	}
+
+	// The untagged fields decode like encoding/json, which produces the same strings.
+	std, _ := json.Marshal(struct {
+		Count int64 `json:"count,string"`
+	}{7})
+	back = jsonOptions{}
+	if err := base.UnmarshalJSON(std, &back); err != nil || back.Count != 7 {
+		t.Errorf("UnmarshalJSON(%s) got: %+v %v.", std, back, err)
+	}
+
+	v = jsonOptions{Created: time.Unix(0, 0).UTC(), Hash: [4]byte{0, 0, 0, 1}}
+	exp = `{"id":"","count":"0","created":"1970-01-01T00:00:00Z","hash":"1"}`
+	if b, err = base.MarshalJSON(v); err != nil || string(b) != exp {
+		t.Errorf("MarshalJSON got: %s %v expected: %s.", b, err, exp)
+	}
+
+	if _, err := base.MarshalJSON(map[float64]jsonKey{1: {}}); err == nil {
+		t.Errorf("MarshalJSON accepted a float map key.")
+	}
+	var m map[uint8]jsonKey
+	for _, s := range []string{`{"256":{}}`, `{"x":{}}`} {
+		if err := base.UnmarshalJSON([]byte(s), &m); err == nil {
+			t.Errorf("UnmarshalJSON(%s) into map[uint8] succeeded, expected an error.", s)
+		}
+	}
+}
+
+type jsonInner struct {
+	Key  []byte `json:"key" base:"58btc"`
+	Name string `json:"name"`
+}
+
+type jsonOuter struct {
+	jsonInner
+	ID uint64 `json:"id" base:"62"`
+}
+
+func TestMarshalJSONEmbedded(t *testing.T) {
+	// Like encoding/json the fields of an unexported embedded struct are promoted.
+	v := jsonOuter{jsonInner{[]byte{1}, "a"}, 62}
+	exp := `{"key":"2","name":"a","id":"10"}`
+	b, err := base.MarshalJSON(v)
+	if err != nil || string(b) != exp {
+		t.Fatalf("MarshalJSON got: %s %v expected: %s.", b, err, exp)
+	}
+	var back jsonOuter
+	if err := base.UnmarshalJSON(b, &back); err != nil || !reflect.DeepEqual(back, v) {
+		t.Errorf("UnmarshalJSON(%s) got: %+v %v expected: %+v.", b, back, err, v)
+	}
+
+	// Of duplicate and case folded keys the last one wins.
+	for s, exp := range map[string]jsonOuter{
+		`{"id":"1","id":"10","NAME":"b","name":"c"}`: {jsonInner{nil, "c"}, 62},
+		`{"id":"10","id":"1","name":"c","NAME":"b"}`: {jsonInner{nil, "b"}, 1},
+	} {
+		var back jsonOuter
+		if err := base.UnmarshalJSON([]byte(s), &back); err != nil || !reflect.DeepEqual(back, exp) {
+			t.Errorf("UnmarshalJSON(%s) got: %+v %v expected: %+v.", s, back, err, exp)
+		}
+	}
+	for _, s := range []string{`{"id":"1"} {}`, `{"id":"1"`, `"id"`} {
+		if err := base.UnmarshalJSON([]byte(s), &back); err == nil {
+			t.Errorf("UnmarshalJSON(%s) succeeded, expected an error.", s)
+		}
+	}
+
+	// A nil embedded pointer to an unexported struct can not be allocated.
+	var p struct {
+		*jsonInner
+		ID uint64 `json:"id" base:"62"`
+	}
+	if err := base.UnmarshalJSON([]byte(`{"key":"2"}`), &p); err == nil {
+		t.Errorf("UnmarshalJSON set a nil embedded pointer to an unexported struct.")
+	}
+	if b, err := base.MarshalJSON(p); err != nil || string(b) != `{"id":""}` {
+		t.Errorf("MarshalJSON got: %s %v expected: {\"id\":\"\"}.", b, err)
+	}
+}
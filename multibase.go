@@ -0,0 +1,201 @@
+// Use of this source code is governed by the CC0 1.0
+// license that can be found in the LICENSE file or here:
+// http://creativecommons.org/publicdomain/zero/1.0/
+
+package base
+
+import (
+	"bytes"
+	"encoding/base32"
+	"encoding/base64"
+	"encoding/hex"
+	"fmt"
+	"time"
+)
+
+// Base58BTC is the bitcoin base58 encoding, registered as "base58btc" and "58btc".
+// Unlike the positional encoding of an Alphabet it keeps every leading zero byte as a leading '1'.
+var Base58BTC Encoding = leadingZeros{mustAlphabet("123456789ABCDEFGHJKLMNPQRSTUVWXYZabcdefghijkmnopqrstuvwxyz")}
+
+// Base58Flickr is the base58 encoding of Flickr short URLs, registered as "base58flickr". Leading zero bytes are kept like in Base58BTC.
+var Base58Flickr Encoding = leadingZeros{mustAlphabet("123456789abcdefghijkmnopqrstuvwxyzABCDEFGHJKLMNPQRSTUVWXYZ")}
+
+// multibases maps the prefix characters of the multibase format to the encodings and their names in the multibase table,
+// every encoding is registered under its name.
+var multibases = []struct {
+	prefix byte
+	name   string
+	enc    Encoding
+}{
+	{'f', "base16", hexEncoding{upper: false}},
+	{'F', "base16upper", hexEncoding{upper: true}},
+	{'b', "base32", stdEncoding{base32.NewEncoding("abcdefghijklmnopqrstuvwxyz234567").WithPadding(base32.NoPadding)}},
+	{'B', "base32upper", stdEncoding{base32.StdEncoding.WithPadding(base32.NoPadding)}},
+	{'c', "base32pad", stdEncoding{base32.NewEncoding("abcdefghijklmnopqrstuvwxyz234567")}},
+	{'C', "base32padupper", stdEncoding{base32.StdEncoding}},
+	{'v', "base32hex", stdEncoding{base32.NewEncoding("0123456789abcdefghijklmnopqrstuv").WithPadding(base32.NoPadding)}},
+	{'V', "base32hexupper", stdEncoding{base32.HexEncoding.WithPadding(base32.NoPadding)}},
+	{'t', "base32hexpad", stdEncoding{base32.NewEncoding("0123456789abcdefghijklmnopqrstuv")}},
+	{'T', "base32hexpadupper", stdEncoding{base32.HexEncoding}},
+	{'k', "base36", leadingZeros{mustAlphabet("0123456789abcdefghijklmnopqrstuvwxyz")}},
+	{'K', "base36upper", leadingZeros{mustAlphabet("0123456789ABCDEFGHIJKLMNOPQRSTUVWXYZ")}},
+	{'z', "base58btc", Base58BTC},
+	{'Z', "base58flickr", Base58Flickr},
+	{'m', "base64", stdEncoding{base64.RawStdEncoding}},
+	{'M', "base64pad", stdEncoding{base64.StdEncoding}},
+	{'u', "base64url", stdEncoding{base64.RawURLEncoding}},
+	{'U', "base64urlpad", stdEncoding{base64.URLEncoding}},
+}
+
+func init() {
+	for _, m := range multibases {
+		Register(m.name, m.enc)
+	}
+	Register("58btc", Base58BTC)
+}
+
+// MultibaseEncoding returns the encoding identified by the multibase prefix character prefix.
+func MultibaseEncoding(prefix byte) (Encoding, error) {
+	for _, m := range multibases {
+		if m.prefix == prefix {
+			return m.enc, nil
+		}
+	}
+	return nil, fmt.Errorf("Unknown multibase prefix %q.", prefix)
+}
+
+// MultibaseEncode returns data encoded with the multibase encoding identified by prefix, starting with the prefix character.
+func MultibaseEncode(prefix byte, data []byte) (string, error) {
+	e, err := MultibaseEncoding(prefix)
+	if err != nil {
+		return "", err
+	}
+	return string(prefix) + string(e.Encode(data)), nil
+}
+
+// MultibaseDecode returns the data of the multibase string s and the prefix character of its encoding.
+func MultibaseDecode(s string) (prefix byte, data []byte, err error) {
+	if s == "" {
+		return 0, nil, fmt.Errorf("Illegal empty multibase string.")
+	}
+	e, err := MultibaseEncoding(s[0])
+	if err != nil {
+		return 0, nil, err
+	}
+	data, err = e.Decode([]byte(s[1:]))
+	if err != nil {
+		return 0, nil, err
+	}
+	return s[0], data, nil
+}
+
+// leadingZeros is the positional encoding of an Alphabet that keeps every leading zero byte as a leading zero digit,
+// the scheme of bitcoin's base58.
+type leadingZeros struct {
+	a *Alphabet
+}
+
+func (l leadingZeros) Encode(src []byte) (r []byte) {
+	if o := loadObserver(); o != nil {
+		defer observe(o, OpEncode, eventName(l), len(src), time.Now(), &r, nil)
+	}
+	return l.encode(src)
+}
+
+func (l leadingZeros) Decode(src []byte) (r []byte, err error) {
+	if o := loadObserver(); o != nil {
+		defer observe(o, OpDecode, eventName(l), len(src), time.Now(), &r, &err)
+	}
+	return l.decode(src)
+}
+
+func (l leadingZeros) encode(src []byte) []byte {
+	z := len(src) - len(bytes.TrimLeft(src, "\x00"))
+	r := bytes.Repeat([]byte{l.a.chars[0]}, z)
+	return append(r, l.a.encode(src[z:])...)
+}
+
+func (l leadingZeros) decode(src []byte) ([]byte, error) {
+	z := len(src) - len(bytes.TrimLeft(src, l.a.chars[:1]))
+	d, err := l.a.decode(src[z:])
+	if err != nil {
+		return nil, err
+	}
+	return append(make([]byte, z, z+len(d)), d...), nil
+}
+
+// stdEncoding adapts the encodings of encoding/base32 and encoding/base64 to Encoding.
+type stdEncoding struct {
+	e interface {
+		EncodedLen(n int) int
+		Encode(dst, src []byte)
+		DecodedLen(n int) int
+		Decode(dst, src []byte) (int, error)
+	}
+}
+
+func (s stdEncoding) Encode(src []byte) (r []byte) {
+	if o := loadObserver(); o != nil {
+		defer observe(o, OpEncode, eventName(s), len(src), time.Now(), &r, nil)
+	}
+	return s.encode(src)
+}
+
+func (s stdEncoding) Decode(src []byte) (r []byte, err error) {
+	if o := loadObserver(); o != nil {
+		defer observe(o, OpDecode, eventName(s), len(src), time.Now(), &r, &err)
+	}
+	return s.decode(src)
+}
+
+func (s stdEncoding) encode(src []byte) []byte {
+	dst := make([]byte, s.e.EncodedLen(len(src)))
+	s.e.Encode(dst, src)
+	return dst
+}
+
+func (s stdEncoding) decode(src []byte) ([]byte, error) {
+	dst := make([]byte, s.e.DecodedLen(len(src)))
+	n, err := s.e.Decode(dst, src)
+	if err != nil {
+		return nil, err
+	}
+	return dst[:n], nil
+}
+
+// hexEncoding is base16 with lower or upper case digits. Decode accepts both cases.
+type hexEncoding struct {
+	upper bool
+}
+
+func (h hexEncoding) Encode(src []byte) (r []byte) {
+	if o := loadObserver(); o != nil {
+		defer observe(o, OpEncode, eventName(h), len(src), time.Now(), &r, nil)
+	}
+	return h.encode(src)
+}
+
+func (h hexEncoding) Decode(src []byte) (r []byte, err error) {
+	if o := loadObserver(); o != nil {
+		defer observe(o, OpDecode, eventName(h), len(src), time.Now(), &r, &err)
+	}
+	return h.decode(src)
+}
+
+func (h hexEncoding) encode(src []byte) []byte {
+	dst := make([]byte, hex.EncodedLen(len(src)))
+	hex.Encode(dst, src)
+	if h.upper {
+		return bytes.ToUpper(dst)
+	}
+	return dst
+}
+
+func (hexEncoding) decode(src []byte) ([]byte, error) {
+	dst := make([]byte, hex.DecodedLen(len(src)))
+	n, err := hex.Decode(dst, src)
+	if err != nil {
+		return nil, err
+	}
+	return dst[:n], nil
+}
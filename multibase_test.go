@@ -0,0 +1,56 @@
+package base_test
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/7i/base"
+)
+
+func TestMultibase(t *testing.T) {
+	// Test vectors from the multibase specification
+	tests := []struct {
+		data []byte
+		exp  string
+	}{
+		{[]byte("yes mani !"), "f796573206d616e692021"},
+		{[]byte("yes mani !"), "F796573206D616E692021"},
+		{[]byte("yes mani !"), "bpfsxgidnmfxgsibb"},
+		{[]byte("yes mani !"), "BPFSXGIDNMFXGSIBB"},
+		{[]byte("yes mani !"), "cpfsxgidnmfxgsibb"},
+		{[]byte("yes mani !"), "vf5in683dc5n6i811"},
+		{[]byte("yes mani !"), "k2lcpzo5yikidynfl"},
+		{[]byte("yes mani !"), "z7paNL19xttacUY"},
+		{[]byte("yes mani !"), "meWVzIG1hbmkgIQ"},
+		{[]byte("yes mani !"), "MeWVzIG1hbmkgIQ=="},
+		{[]byte("yes mani !"), "ueWVzIG1hbmkgIQ"},
+		{[]byte("\x00yes mani !"), "z17paNL19xttacUY"},
+		{[]byte("\x00\x00yes mani !"), "z117paNL19xttacUY"},
+		{[]byte("\x00yes mani !"), "k02lcpzo5yikidynfl"},
+		{[]byte{0, 0}, "z11"},
+		{[]byte{}, "z"},
+	}
+	for _, test := range tests {
+		res, err := base.MultibaseEncode(test.exp[0], test.data)
+		if err != nil || res != test.exp {
+			t.Errorf("MultibaseEncode(%q, %q) got: %s %v expected: %s.", test.exp[0], test.data, res, err, test.exp)
+		}
+		prefix, dec, err := base.MultibaseDecode(test.exp)
+		if err != nil || prefix != test.exp[0] || !bytes.Equal(dec, test.data) {
+			t.Errorf("MultibaseDecode(%s) got: %q %q %v expected: %q.", test.exp, prefix, dec, err, test.data)
+		}
+	}
+
+	for _, s := range []string{"", "Qabc", "z0OIl", "bpfsxgidnmfxgsib1", "fxy"} {
+		if _, _, err := base.MultibaseDecode(s); err == nil {
+			t.Errorf("MultibaseDecode(%q) succeeded, expected an error.", s)
+		}
+	}
+
+	if e, err := base.Lookup("58btc"); err != nil || e != base.Base58BTC {
+		t.Errorf("Lookup(58btc) got: %v %v.", e, err)
+	}
+	if e, err := base.Lookup("base64url"); err != nil || string(e.Encode([]byte{0xfb, 0xff})) != "-_8" {
+		t.Errorf("Lookup(base64url) got: %v %v.", e, err)
+	}
+}
@@ -0,0 +1,71 @@
+// Use of this source code is governed by the CC0 1.0
+// license that can be found in the LICENSE file or here:
+// http://creativecommons.org/publicdomain/zero/1.0/
+
+package base
+
+import (
+	"crypto/rand"
+	"fmt"
+	"io"
+	"math/bits"
+)
+
+// NanoIDAlphabet is the URL safe alphabet of the JavaScript nanoid package, in the same order.
+var NanoIDAlphabet = mustAlphabet("useandom-26T198340PX75pxJACKVERYMINDBUSHWOLF_GQZbfghjklqvwyzrict")
+
+// NanoID returns a random id of size characters from NanoIDAlphabet generated like nanoid() of the JavaScript nanoid package.
+//
+// The default size of nanoid is 21 characters.
+func NanoID(size int) (string, error) {
+	return nanoID(rand.Reader, size)
+}
+
+// CustomNanoID returns a random id of size characters from a generated like customAlphabet(a, size)() of the JavaScript nanoid package.
+//
+// Random bytes are masked to the smallest power of two covering a and values outside of a are rejected, so every character is equally likely.
+func CustomNanoID(a *Alphabet, size int) (string, error) {
+	return customNanoID(rand.Reader, a, size)
+}
+
+func nanoID(r io.Reader, size int) (string, error) {
+	if size < 1 {
+		return "", fmt.Errorf("Illegal NanoID size %d.", size)
+	}
+	b := make([]byte, size)
+	if _, err := io.ReadFull(r, b); err != nil {
+		return "", err
+	}
+	// nanoid consumes the random bytes from the end.
+	id := make([]byte, size)
+	for i := range id {
+		id[i] = NanoIDAlphabet.chars[b[size-1-i]&63]
+	}
+	return string(id), nil
+}
+
+func customNanoID(r io.Reader, a *Alphabet, size int) (string, error) {
+	if size < 1 {
+		return "", fmt.Errorf("Illegal NanoID size %d.", size)
+	}
+	k := a.Len()
+	mask := 2<<(bits.Len32(uint32(k-1)|1)-1) - 1
+	// The same floating point expression as nanoid, so the number of random bytes read per round matches.
+	step := int(1.6*float64(mask)*float64(size)/float64(k)) + 1
+
+	id := make([]byte, 0, size)
+	b := make([]byte, step)
+	for {
+		if _, err := io.ReadFull(r, b); err != nil {
+			return "", err
+		}
+		for j := step - 1; j >= 0; j-- {
+			if v := int(b[j]) & mask; v < k {
+				id = append(id, a.chars[v])
+				if len(id) == size {
+					return string(id), nil
+				}
+			}
+		}
+	}
+}
@@ -0,0 +1,85 @@
+package base_test
+
+import (
+	"testing"
+
+	"github.com/7i/base"
+)
+
+// seqReader returns the bytes start, start+step, ... modulo 256.
+type seqReader struct {
+	next, step int
+}
+
+func (r *seqReader) Read(b []byte) (int, error) {
+	for i := range b {
+		b[i] = byte(r.next)
+		r.next = (r.next + r.step) % 256
+	}
+	return len(b), nil
+}
+
+// The expected ids are what the JavaScript nanoid package returns when crypto.getRandomValues yields the same bytes.
+func TestNanoIDCompatible(t *testing.T) {
+	id, err := base.NanoIDFrom(&seqReader{0, 1}, 21)
+	if err != nil || id != "7XP043891T62-modnaesu" {
+		t.Errorf("NanoID got: %q %v expected: \"7XP043891T62-modnaesu\".", id, err)
+	}
+
+	tests := []struct {
+		alphabet string
+		size     int
+		exp      string
+	}{
+		{"0123456789", 10, "3945061728"},
+		{"0123456789abcdef", 12, "50b61c72d83e"},
+		{"ABCDEFGHIJ", 30, "DJEFAGBHCIDJEFAGBHCIDJEFAGBHCI"},
+	}
+	for _, test := range tests {
+		a, _ := base.NewAlphabet(test.alphabet)
+		id, err := base.CustomNanoIDFrom(&seqReader{11, 37}, a, test.size)
+		if err != nil || id != test.exp {
+			t.Errorf("CustomNanoID(%s, %d) got: %q %v expected: %q.", test.alphabet, test.size, id, err, test.exp)
+		}
+	}
+}
+
+func TestNanoID(t *testing.T) {
+	id, err := base.NanoID(21)
+	if err != nil || len(id) != 21 {
+		t.Fatalf("NanoID got: %q %v.", id, err)
+	}
+	for i := 0; i < len(id); i++ {
+		if base.NanoIDAlphabet.Index(id[i]) < 0 {
+			t.Errorf("NanoID got %q with a character outside of the alphabet.", id)
+		}
+	}
+
+	// Every character of an alphabet that is not a power of two is equally likely
+	a, _ := base.NewAlphabet("abcde")
+	counts := map[rune]int{}
+	for i := 0; i < 200; i++ {
+		id, err := base.CustomNanoID(a, 50)
+		if err != nil {
+			t.Fatal(err)
+		}
+		for _, c := range id {
+			counts[c]++
+		}
+	}
+	for _, c := range "abcde" {
+		if n := counts[c]; n < 1700 || n > 2300 {
+			t.Errorf("CustomNanoID produced %c %d times out of 10000, expected about 2000.", c, n)
+		}
+	}
+	if len(counts) != 5 {
+		t.Errorf("CustomNanoID produced characters outside of the alphabet: %v.", counts)
+	}
+
+	if _, err := base.NanoID(0); err == nil {
+		t.Errorf("NanoID accepted size 0.")
+	}
+	if _, err := base.CustomNanoID(a, -1); err == nil {
+		t.Errorf("CustomNanoID accepted size -1.")
+	}
+}
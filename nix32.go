@@ -0,0 +1,105 @@
+// Use of this source code is governed by the CC0 1.0
+// license that can be found in the LICENSE file or here:
+// http://creativecommons.org/publicdomain/zero/1.0/
+
+package base
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+// Nix32 is the base32 encoding of the Nix package manager used for hashes and store paths, registered as "nix32".
+//
+// It uses the alphabet 0123456789abcdfghijklmnpqrsvwxyz, without e, o, u and t, and reads the bits of the input in reverse,
+// the last character holds the lowest bits of the first byte. It is neither RFC 4648 base32 nor a positional base32 number.
+var Nix32 Encoding = nix32{}
+
+var nix32Alphabet = mustAlphabet("0123456789abcdfghijklmnpqrsvwxyz")
+
+type nix32 struct{}
+
+// Encode returns src encoded like Nix's printHash32.
+func (n nix32) Encode(src []byte) (r []byte) {
+	if o := loadObserver(); o != nil {
+		defer observe(o, OpEncode, eventName(n), len(src), time.Now(), &r, nil)
+	}
+	return n.encode(src)
+}
+
+// Decode returns the data encoded in src like Nix's parseHash32, rejecting lengths no Nix hash can have and non zero padding bits.
+func (n nix32) Decode(src []byte) (r []byte, err error) {
+	if o := loadObserver(); o != nil {
+		defer observe(o, OpDecode, eventName(n), len(src), time.Now(), &r, &err)
+	}
+	return n.decode(src)
+}
+
+func (nix32) encode(src []byte) []byte {
+	if len(src) == 0 {
+		return []byte{}
+	}
+	l := (len(src)*8-1)/5 + 1
+	r := make([]byte, 0, l)
+	for n := l - 1; n >= 0; n-- {
+		b := n * 5
+		i, j := b/8, uint(b%8)
+		c := src[i] >> j
+		if i < len(src)-1 {
+			c |= src[i+1] << (8 - j)
+		}
+		r = append(r, nix32Alphabet.chars[c&0x1f])
+	}
+	return r
+}
+
+func (nix32) decode(src []byte) ([]byte, error) {
+	size := len(src) * 5 / 8
+	if len(src) > 0 && (size == 0 || (size*8-1)/5+1 != len(src)) {
+		return nil, fmt.Errorf("Illegal Nix base32 length %d.", len(src))
+	}
+	r := make([]byte, size)
+	for n := 0; n < len(src); n++ {
+		c := src[len(src)-n-1]
+		d := nix32Alphabet.dec[c]
+		if d < 0 {
+			return nil, fmt.Errorf("Illegal character %q in Nix base32 decoding.", c)
+		}
+		b := n * 5
+		i, j := b/8, uint(b%8)
+		r[i] |= byte(d << j)
+		if hi := byte(d >> (8 - j)); i < size-1 {
+			r[i+1] |= hi
+		} else if hi != 0 {
+			return nil, fmt.Errorf("Illegal Nix base32 padding bits.")
+		}
+	}
+	return r, nil
+}
+
+// NixCompressHash folds h to size bytes by XORing byte i of h into byte i%size, like Nix's compressHash.
+// Store paths use a SHA-256 hash compressed to 20 bytes. NixCompressHash returns an error if size is not positive.
+func NixCompressHash(h []byte, size int) ([]byte, error) {
+	if size <= 0 {
+		return nil, fmt.Errorf("Illegal Nix compressed hash size %d.", size)
+	}
+	r := make([]byte, size)
+	for i, c := range h {
+		r[i%size] ^= c
+	}
+	return r, nil
+}
+
+// ParseNixStorePath splits a store path like /nix/store/<hash>-<name> and returns the decoded 20 byte hash and the name.
+func ParseNixStorePath(path string) (hash []byte, name string, err error) {
+	base := path[strings.LastIndexByte(path, '/')+1:]
+	if len(base) < 34 || base[32] != '-' {
+		return nil, "", fmt.Errorf("Illegal Nix store path %q.", path)
+	}
+	hash, err = Nix32.Decode([]byte(base[:32]))
+	if err != nil {
+		return nil, "", err
+	}
+	return hash, base[33:], nil
+}
@@ -0,0 +1,86 @@
+package base_test
+
+import (
+	"bytes"
+	"crypto/sha1"
+	"crypto/sha256"
+	"encoding/hex"
+	"testing"
+
+	"github.com/7i/base"
+)
+
+func TestNix32(t *testing.T) {
+	sha256abc := sha256.Sum256([]byte("abc"))
+	sha256empty := sha256.Sum256(nil)
+	sha1abc := sha1.Sum([]byte("abc"))
+	// Known hashes from the Nix test suite, nix-hash --type sha256 --to-base32
+	tests := []struct {
+		data []byte
+		exp  string
+	}{
+		{sha256abc[:], "1b8m03r63zqhnjf7l5wnldhh7c134ap5vpj0850ymkq1iyzicy5s"},
+		{sha256empty[:], "0mdqa9w1p6cmli6976v4wi0sw9r4p5prkj7lzfd1877wk11c9c73"},
+		{sha1abc[:], "kpcd173cq987hw957sx6m0868wv3x6d9"},
+		{[]byte{}, ""},
+		{[]byte{0x1f}, "0z"},
+		{[]byte{0xff}, "7z"},
+	}
+	for _, test := range tests {
+		res := base.Nix32.Encode(test.data)
+		if string(res) != test.exp {
+			t.Errorf("Nix32.Encode(%x) got: %s expected: %s.", test.data, res, test.exp)
+		}
+		dec, err := base.Nix32.Decode([]byte(test.exp))
+		if err != nil || !bytes.Equal(dec, test.data) {
+			t.Errorf("Nix32.Decode(%s) got: %x %v expected: %x.", test.exp, dec, err, test.data)
+		}
+	}
+
+	if e, err := base.Lookup("nix32"); err != nil || e != base.Nix32 {
+		t.Errorf("Lookup(nix32) got: %v %v.", e, err)
+	}
+}
+
+func TestNix32Errors(t *testing.T) {
+	for _, s := range []string{
+		"1b8m03r63zqhnjf7l5wnldhh7c134ap5vpj0850ymkq1iyzicy5",  // length of no hash
+		"1b8m03r63zqhnjf7l5wnldhh7c134ap5vpj0850ymkq1iyzicy5e", // e is not part of the alphabet
+		"8z", // padding bits set
+		"z",
+	} {
+		if _, err := base.Nix32.Decode([]byte(s)); err == nil {
+			t.Errorf("Nix32.Decode(%s) succeeded, expected an error.", s)
+		}
+	}
+}
+
+func TestNixStorePath(t *testing.T) {
+	// The SHA-256 of abc from nix-hash --type sha256 --to-base32, compressed to 160 bits like the hashes of store paths.
+	h, _ := base.Nix32.Decode([]byte("1b8m03r63zqhnjf7l5wnldhh7c134ap5vpj0850ymkq1iyzicy5s"))
+	c, err := base.NixCompressHash(h, 20)
+	if res := base.Nix32.Encode(c); err != nil || string(res) != "ldhh7c134ap5swsm86rqnc0i7cinqvrc" {
+		t.Errorf("NixCompressHash got: %s %v expected: ldhh7c134ap5swsm86rqnc0i7cinqvrc.", res, err)
+	}
+	for _, size := range []int{0, -1} {
+		if _, err := base.NixCompressHash(h, size); err == nil {
+			t.Errorf("NixCompressHash with size %d succeeded, expected an error.", size)
+		}
+	}
+
+	// The store path of the Nix manual.
+	path := "/nix/store/b6gvzjyb2pg0kjfwrjmg1vfhh54ad73z-firefox-33.1"
+	hash, name, err := base.ParseNixStorePath(path)
+	if err != nil || len(hash) != 20 || string(base.Nix32.Encode(hash)) != "b6gvzjyb2pg0kjfwrjmg1vfhh54ad73z" || name != "firefox-33.1" {
+		t.Errorf("ParseNixStorePath(%s) got: %x %q %v.", path, hash, name, err)
+	}
+	hash, _, err = base.ParseNixStorePath("/nix/store/kpcd173cq987hw957sx6m0868wv3x6d9-abc")
+	if err != nil || hex.EncodeToString(hash) != "a9993e364706816aba3e25717850c26c9cd0d89d" {
+		t.Errorf("ParseNixStorePath got: %x %v expected the SHA-1 of abc.", hash, err)
+	}
+	for _, p := range []string{"/nix/store/short-name", "/nix/store/kpcd173cq987hw957sx6m0868wv3x6d9_abc", "/nix/store/kpcd173cq987hw957sx6m0868wv3x6d9-"} {
+		if _, _, err := base.ParseNixStorePath(p); err == nil {
+			t.Errorf("ParseNixStorePath(%s) succeeded, expected an error.", p)
+		}
+	}
+}
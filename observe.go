@@ -0,0 +1,166 @@
+// Use of this source code is governed by the CC0 1.0
+// license that can be found in the LICENSE file or here:
+// http://creativecommons.org/publicdomain/zero/1.0/
+
+package base
+
+import (
+	"encoding/json"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// Op is the operation of an Event.
+type Op string
+
+const (
+	OpEncode Op = "encode"
+	OpDecode Op = "decode"
+)
+
+// Event describes one call of Encode, Decode or the Encode and Decode methods of an Alphabet or another Encoding of the package.
+// An encoding built on another one, like Base58Check on Base58BTC, reports a single event of its own. Encodings registered by
+// other packages are not observed.
+type Event struct {
+	Op Op
+	// Encoding is the base as a decimal number for Encode, Decode and the alphabets of Digits and Lookup, e.g. "62".
+	// Other encodings use their first registered name in sorted order, e.g. "crockford" or "58btc" for Base58BTC,
+	// and unregistered alphabets "alphabet" followed by their size.
+	Encoding string
+	// In and Out are the sizes of the input and the output in bytes.
+	In, Out  int
+	Duration time.Duration
+	Err      error
+}
+
+// Observer is notified of every Event once set with SetObserver. Observe is called synchronously and must be safe for concurrent use.
+type Observer interface {
+	Observe(e Event)
+}
+
+// ObserverFunc adapts a function to an Observer.
+type ObserverFunc func(e Event)
+
+func (f ObserverFunc) Observe(e Event) {
+	f(e)
+}
+
+var observer atomic.Pointer[Observer]
+
+// SetObserver sets the Observer notified of every Event, nil removes it. Without an Observer the only cost is an atomic load per call.
+func SetObserver(o Observer) {
+	if o == nil {
+		observer.Store(nil)
+		return
+	}
+	observer.Store(&o)
+}
+
+// loadObserver returns the current Observer or nil.
+func loadObserver() Observer {
+	if o := observer.Load(); o != nil {
+		return *o
+	}
+	return nil
+}
+
+// observe reports an event to o, it is deferred with pointers to the named results of the observed function.
+func observe(o Observer, op Op, enc string, in int, start time.Time, out *[]byte, err *error) {
+	e := Event{Op: op, Encoding: enc, In: in, Out: len(*out), Duration: time.Since(start)}
+	if err != nil {
+		e.Err = *err
+	}
+	o.Observe(e)
+}
+
+// Metrics is an Observer that collects Stats per operation and encoding. It implements expvar.Var so that it can be published
+// together with the other variables of a program:
+//
+//	m := base.NewMetrics()
+//	expvar.Publish("base", m)
+//	base.SetObserver(m)
+type Metrics struct {
+	mu    sync.RWMutex
+	stats map[string]*opStats
+}
+
+// Stats are the totals of all events of one operation and encoding.
+type Stats struct {
+	Count    int64         `json:"count"`
+	Errors   int64         `json:"errors"`
+	BytesIn  int64         `json:"bytes_in"`
+	BytesOut int64         `json:"bytes_out"`
+	Duration time.Duration `json:"duration_ns"`
+}
+
+// ErrorRate returns the fraction of events that failed.
+func (s Stats) ErrorRate() float64 {
+	if s.Count == 0 {
+		return 0
+	}
+	return float64(s.Errors) / float64(s.Count)
+}
+
+type opStats struct {
+	count, errors, bytesIn, bytesOut, nanos atomic.Int64
+}
+
+// NewMetrics returns an empty Metrics.
+func NewMetrics() *Metrics {
+	return &Metrics{stats: map[string]*opStats{}}
+}
+
+// Observe implements Observer.
+func (m *Metrics) Observe(e Event) {
+	key := string(e.Op) + "/" + e.Encoding
+	m.mu.RLock()
+	s := m.stats[key]
+	m.mu.RUnlock()
+	if s == nil {
+		m.mu.Lock()
+		if s = m.stats[key]; s == nil {
+			s = &opStats{}
+			m.stats[key] = s
+		}
+		m.mu.Unlock()
+	}
+	s.count.Add(1)
+	if e.Err != nil {
+		s.errors.Add(1)
+	}
+	s.bytesIn.Add(int64(e.In))
+	s.bytesOut.Add(int64(e.Out))
+	s.nanos.Add(int64(e.Duration))
+}
+
+// Stats returns a snapshot of the collected Stats keyed by operation and encoding, e.g. "decode/62".
+func (m *Metrics) Stats() map[string]Stats {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	r := make(map[string]Stats, len(m.stats))
+	for key, s := range m.stats {
+		r[key] = Stats{
+			Count:    s.count.Load(),
+			Errors:   s.errors.Load(),
+			BytesIn:  s.bytesIn.Load(),
+			BytesOut: s.bytesOut.Load(),
+			Duration: time.Duration(s.nanos.Load()),
+		}
+	}
+	return r
+}
+
+// String implements expvar.Var, it returns the Stats as a JSON object including the error rate of every entry.
+func (m *Metrics) String() string {
+	type entry struct {
+		Stats
+		ErrorRate float64 `json:"error_rate"`
+	}
+	r := map[string]entry{}
+	for key, s := range m.Stats() {
+		r[key] = entry{s, s.ErrorRate()}
+	}
+	b, _ := json.Marshal(r)
+	return string(b)
+}
@@ -0,0 +1,96 @@
+package base_test
+
+import (
+	"encoding/json"
+	"errors"
+	"testing"
+
+	"github.com/7i/base"
+)
+
+func TestObserver(t *testing.T) {
+	var events []base.Event
+	base.SetObserver(base.ObserverFunc(func(e base.Event) { events = append(events, e) }))
+	defer base.SetObserver(nil)
+
+	base.Encode([]byte{0xff, 0xff}, 16)
+	base.Decode([]byte("xyz"), 16)
+	a, _ := base.NewAlphabet("01")
+	a.Encode([]byte{5})
+	d, _ := base.Digits(16)
+	d.Encode([]byte{1})
+	e16, _ := base.Lookup("16")
+	e16.Decode([]byte("ff"))
+	base.Crockford.Encode([]byte{1})
+	base.Base58BTC.Encode([]byte{0, 0, 1})
+	base.Base58Check.Decode([]byte("1111"))
+	base.Nix32.Encode([]byte{1})
+	base.GitBase85.Decode([]byte("00000"))
+	base.CrockfordCheck.Encode([]byte{1})
+	e64, _ := base.Lookup("base64")
+	e64.Encode([]byte{1})
+
+	if len(events) != 12 {
+		t.Fatalf("Observer got %d events expected: 12.", len(events))
+	}
+	e := events[0]
+	if e.Op != base.OpEncode || e.Encoding != "16" || e.In != 2 || e.Out != 4 || e.Err != nil || e.Duration < 0 {
+		t.Errorf("Encode event got: %+v.", e)
+	}
+	e = events[1]
+	if e.Op != base.OpDecode || e.Encoding != "16" || e.In != 3 || e.Out != 0 || e.Err == nil {
+		t.Errorf("Decode event got: %+v.", e)
+	}
+	e = events[2]
+	if e.Op != base.OpEncode || e.Encoding != "alphabet2" || e.In != 1 || e.Out != 3 || e.Err != nil {
+		t.Errorf("Alphabet.Encode event got: %+v.", e)
+	}
+	// The same base is reported under the same name however it is used.
+	for i, exp := range []string{"16", "16", "crockford", "58btc", "base58check", "nix32", "git85", "crockfordcheck", "base64"} {
+		if e := events[3+i]; e.Encoding != exp {
+			t.Errorf("Event %d got encoding: %s expected: %s.", 3+i, e.Encoding, exp)
+		}
+	}
+	// Encodings built on an alphabet report their own input and output, including leading zeros.
+	if e := events[6]; e.Op != base.OpEncode || e.In != 3 || e.Out != 3 {
+		t.Errorf("Base58BTC.Encode event got: %+v.", e)
+	}
+	if e := events[7]; e.Op != base.OpDecode || e.In != 4 || e.Err == nil {
+		t.Errorf("Base58Check.Decode event got: %+v.", e)
+	}
+
+	base.SetObserver(nil)
+	base.Encode([]byte{1}, 16)
+	if len(events) != 12 {
+		t.Errorf("Observer called after SetObserver(nil).")
+	}
+}
+
+func TestMetrics(t *testing.T) {
+	m := base.NewMetrics()
+	base.SetObserver(m)
+	defer base.SetObserver(nil)
+
+	for i := 0; i < 3; i++ {
+		base.Encode([]byte("hello"), 62)
+	}
+	base.Decode([]byte("7tQLFHz"), 62)
+	base.Decode([]byte("7tQLFH-"), 62)
+	m.Observe(base.Event{Op: base.OpDecode, Encoding: "custom", Err: errors.New("failed")})
+
+	stats := m.Stats()
+	if s := stats["encode/62"]; s.Count != 3 || s.BytesIn != 15 || s.BytesOut != 21 || s.Errors != 0 {
+		t.Errorf("Stats of encode/62 got: %+v.", s)
+	}
+	if s := stats["decode/62"]; s.Count != 2 || s.Errors != 1 || s.BytesIn != 14 || s.BytesOut != 5 || s.ErrorRate() != 0.5 {
+		t.Errorf("Stats of decode/62 got: %+v.", s)
+	}
+
+	var published map[string]map[string]float64
+	if err := json.Unmarshal([]byte(m.String()), &published); err != nil {
+		t.Fatalf("Metrics.String is not JSON: %v.", err)
+	}
+	if p := published["decode/custom"]; p["count"] != 1 || p["errors"] != 1 || p["error_rate"] != 1 {
+		t.Errorf("Metrics.String of decode/custom got: %v.", p)
+	}
+}
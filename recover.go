@@ -0,0 +1,124 @@
+// Use of this source code is governed by the CC0 1.0
+// license that can be found in the LICENSE file or here:
+// http://creativecommons.org/publicdomain/zero/1.0/
+
+package base
+
+import (
+	"fmt"
+	"math/big"
+	"strings"
+)
+
+// DefaultRecoverLimit is the maximum number of candidates Recover tries if its limit is 0.
+const DefaultRecoverLimit = 1 << 20
+
+// Completion is a string completing the pattern of Recover.
+type Completion struct {
+	Text string
+	// Data is the result of decoding Text.
+	Data []byte
+}
+
+// RecoverCount returns the number of candidates Recover tries for pattern, the product of the number of characters at every ? of pattern.
+func RecoverCount(pattern string, enc Encoding) (*big.Int, error) {
+	choices, err := recoverChoices(pattern, enc)
+	if err != nil {
+		return nil, err
+	}
+	return countChoices(choices), nil
+}
+
+// Recover returns the completions of pattern that enc decodes without error and keep accepts, in the order of the digits of enc.
+// Every ? in pattern stands for an unreadable character, e.g. "ab?d-??xz", and is replaced by every digit of enc,
+// or at the end of a checksummed encoding like CrockfordCheck by every digit and check symbol. Other characters must be accepted by enc.
+// For encodings where ? is a digit, like RFC1924, every ? is a wildcard.
+//
+// keep may be nil, it can check the data with external knowledge, e.g. a database lookup. The checksum of enc already limits the completions,
+// a checksum of 32 bits like Base58Check leaves about one wrong completion per 4 billion candidates.
+// Recover returns an error without trying any candidate if their number, see RecoverCount, exceeds limit, 0 means DefaultRecoverLimit.
+func Recover(pattern string, enc Encoding, limit int, keep func(c Completion) bool) ([]Completion, error) {
+	if limit <= 0 {
+		limit = DefaultRecoverLimit
+	}
+	choices, err := recoverChoices(pattern, enc)
+	if err != nil {
+		return nil, err
+	}
+	if n := countChoices(choices); n.Cmp(big.NewInt(int64(limit))) > 0 {
+		return nil, fmt.Errorf("Too many candidates %s for limit %d.", n, limit)
+	}
+	var r []Completion
+	idx := make([]int, len(choices))
+	buf := make([]byte, len(choices))
+	for i, c := range choices {
+		buf[i] = c[0]
+	}
+	for {
+		if d, err := enc.Decode(buf); err == nil {
+			c := Completion{string(buf), d}
+			if keep == nil || keep(c) {
+				r = append(r, c)
+			}
+		}
+		// Advance the last position like an odometer.
+		i := len(choices) - 1
+		for ; i >= 0; i-- {
+			if idx[i]++; idx[i] < len(choices[i]) {
+				buf[i] = choices[i][idx[i]]
+				break
+			}
+			idx[i] = 0
+			buf[i] = choices[i][0]
+		}
+		if i < 0 {
+			return r, nil
+		}
+	}
+}
+
+func countChoices(choices []string) *big.Int {
+	n := big.NewInt(1)
+	for _, c := range choices {
+		if len(c) > 1 {
+			n.Mul(n, big.NewInt(int64(len(c))))
+		}
+	}
+	return n
+}
+
+// recoverChoices returns the possible characters of every position of pattern.
+func recoverChoices(pattern string, enc Encoding) ([]string, error) {
+	syn, err := syntaxOf(enc)
+	if err != nil {
+		return nil, err
+	}
+	if pattern == "" {
+		return nil, fmt.Errorf("Empty pattern.")
+	}
+	// Wildcards are replaced by the digits in their canonical case, and check symbols without their lower case alternatives.
+	digits := syn.alphabet.String()
+	var checks []byte
+	for _, c := range []byte(syn.check) {
+		if 'a' <= c && c <= 'z' && strings.IndexByte(syn.check, c-'a'+'A') >= 0 {
+			continue
+		}
+		checks = append(checks, c)
+	}
+	valid := syn.digits + syn.check + syn.separators
+	r := make([]string, len(pattern))
+	for i := 0; i < len(pattern); i++ {
+		c := pattern[i]
+		switch {
+		case c == '?' && i == len(pattern)-1:
+			r[i] = digits + string(checks)
+		case c == '?':
+			r[i] = digits
+		case strings.IndexByte(valid, c) >= 0:
+			r[i] = pattern[i : i+1]
+		default:
+			return nil, fmt.Errorf("Illegal character %q in pattern.", c)
+		}
+	}
+	return r, nil
+}
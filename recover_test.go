@@ -0,0 +1,55 @@
+package base_test
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/7i/base"
+)
+
+func TestRecover(t *testing.T) {
+	const addr = "16UwLL9Risc3QfPqBUvKofHmBQ7wMtjvM"
+	data, _ := base.Base58Check.Decode([]byte(addr))
+	res, err := base.Recover("16UwLL9Ris?3QfPqBUvKofHmBQ7wMtj?M", base.Base58Check, 0, nil)
+	if err != nil || len(res) != 1 || res[0].Text != addr || !bytes.Equal(res[0].Data, data) {
+		t.Errorf("Recover of a damaged address got: %v %v expected: %s.", res, err, addr)
+	}
+	if n, err := base.RecoverCount("16UwLL9Ris?3QfPqBUvKofHmBQ7wMtj?M", base.Base58Check); err != nil || n.Int64() != 58*58 {
+		t.Errorf("RecoverCount of a damaged address got: %v %v expected: %d.", n, err, 58*58)
+	}
+
+	// One in 37 completions of a Crockford check code is valid, the rest has to be filtered by other means.
+	res, err = base.Recover("1-6-?-D", base.CrockfordCheck, 0, nil)
+	if err != nil || len(res) != 1 || res[0].Text != "1-6-J-D" {
+		t.Errorf("Recover(1-6-?-D) got: %v %v expected: 1-6-J-D.", res, err)
+	}
+	res, err = base.Recover("16J?", base.CrockfordCheck, 0, nil)
+	if err != nil || len(res) != 1 || res[0].Text != "16JD" {
+		t.Errorf("Recover(16J?) got: %v %v expected: 16JD.", res, err)
+	}
+	all, _ := base.Recover("???", base.CrockfordCheck, 0, nil)
+	if len(all) != 32*32 {
+		t.Errorf("Recover(???) got %d completions expected: %d.", len(all), 32*32)
+	}
+	known := map[string]bool{"\x01\x23": true, "\x04\xd2": true}
+	res, err = base.Recover("???", base.CrockfordCheck, 0, func(c base.Completion) bool { return known[string(c.Data)] })
+	if err != nil || len(res) != 1 || res[0].Text != "93*" {
+		t.Errorf("Recover(???) of known values got: %v %v expected: 93*.", res, err)
+	}
+	res, err = base.Recover("????", base.CrockfordCheck, 2<<20, func(c base.Completion) bool { return known[string(c.Data)] })
+	if err != nil || len(res) != 2 || res[0].Text != "093*" || res[1].Text != "16JD" {
+		t.Errorf("Recover(????) of known values got: %v %v expected: 093* 16JD.", res, err)
+	}
+
+	if _, err := base.Recover("?????", base.Base58Check, 0, nil); err == nil {
+		t.Errorf("Recover of 58^5 candidates succeeded, expected an error.")
+	}
+	if _, err := base.Recover("??", base.Base58Check, 100, nil); err == nil {
+		t.Errorf("Recover of 58^2 candidates with limit 100 succeeded, expected an error.")
+	}
+	for _, p := range []string{"", "0?", "ab!?"} {
+		if _, err := base.Recover(p, base.Base58Check, 0, nil); err == nil {
+			t.Errorf("Recover(%q) succeeded, expected an error.", p)
+		}
+	}
+}
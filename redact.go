@@ -0,0 +1,153 @@
+// Use of this source code is governed by the CC0 1.0
+// license that can be found in the LICENSE file or here:
+// http://creativecommons.org/publicdomain/zero/1.0/
+
+package base
+
+import (
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/binary"
+	"fmt"
+	"log/slog"
+	"strings"
+	"sync"
+	"sync/atomic"
+)
+
+// Redacted is an encoded secret, like an API token, that is rendered masked by fmt, log/slog and encoding/json:
+//
+//	slog.Info("request", "token", base.Redacted(token))
+//
+// logs the token as e.g. sk_live_4f8a…9c2e#h:k3p. A known token prefix, see AddRedactedPrefix, is kept. Of the rest nothing is
+// shown if it is shorter than 16 characters, otherwise at most 4 characters and a tenth of its length are shown at each end.
+// The fingerprint after #h: is a short keyed hash of the whole secret encoded with the digits of base 62, so identical secrets
+// can be correlated in logs, see SetFingerprintKey. Only an explicit conversion back to string reveals the secret.
+type Redacted string
+
+// redactMinLen is the minimum length of a secret without its prefix of which characters are shown.
+const redactMinLen = 16
+
+// redactedPrefixes holds the prefixes kept by Redacted, the well known prefixes of API tokens that only tell their type.
+var redactedPrefixes = struct {
+	sync.RWMutex
+	p []string
+}{p: []string{
+	"sk_live_", "sk_test_", "pk_live_", "pk_test_", "rk_live_", "rk_test_", "whsec_",
+	"ghp_", "gho_", "ghu_", "ghs_", "ghr_", "github_pat_",
+	"glpat-", "xoxb-", "xoxp-", "xoxa-", "xapp-",
+}}
+
+// AddRedactedPrefix makes Redacted keep prefix, e.g. the type prefix of a TypeID followed by its underscore, until remove is called.
+// Of several matching prefixes the longest is kept. Prefixes should only tell the type of a secret, never a part of it.
+func AddRedactedPrefix(prefix string) (remove func()) {
+	redactedPrefixes.Lock()
+	defer redactedPrefixes.Unlock()
+	redactedPrefixes.p = append(redactedPrefixes.p, prefix)
+	var once sync.Once
+	return func() {
+		once.Do(func() {
+			redactedPrefixes.Lock()
+			defer redactedPrefixes.Unlock()
+			p := redactedPrefixes.p
+			for i := len(p) - 1; i >= 0; i-- {
+				if p[i] == prefix {
+					redactedPrefixes.p = append(p[:i:i], p[i+1:]...)
+					return
+				}
+			}
+		})
+	}
+}
+
+// redactedPrefix returns the longest prefix of s kept by Redacted.
+func redactedPrefix(s string) string {
+	redactedPrefixes.RLock()
+	defer redactedPrefixes.RUnlock()
+	r := ""
+	for _, p := range redactedPrefixes.p {
+		if len(p) > len(r) && strings.HasPrefix(s, p) {
+			r = p
+		}
+	}
+	return r
+}
+
+// fingerprintLen is the number of base 62 digits of a fingerprint, about 18 bits. It is short on purpose,
+// so that a fingerprint identifies a secret among the few in a log but can not confirm a guessed secret.
+const fingerprintLen = 3
+
+var fingerprintKey atomic.Pointer[[]byte]
+
+// defaultFingerprintKey is generated on first use if no key is set.
+var defaultFingerprintKey = sync.OnceValue(func() []byte {
+	k := make([]byte, 32)
+	rand.Read(k)
+	return k
+})
+
+// SetFingerprintKey sets the key of the fingerprints of Redacted. Without a key, or after setting nil, a random key is used
+// and fingerprints only correlate within one process. Services that share a key get the same fingerprints for the same secret.
+func SetFingerprintKey(key []byte) {
+	if key == nil {
+		fingerprintKey.Store(nil)
+		return
+	}
+	k := append([]byte(nil), key...)
+	fingerprintKey.Store(&k)
+}
+
+// Fingerprint returns the keyed hash of r rendered after #h:.
+func (r Redacted) Fingerprint() string {
+	key := defaultFingerprintKey()
+	if k := fingerprintKey.Load(); k != nil {
+		key = *k
+	}
+	h := hmac.New(sha256.New, key)
+	h.Write([]byte(r))
+	v := binary.BigEndian.Uint64(h.Sum(nil))
+	var b [fingerprintLen]byte
+	for i := range b {
+		b[i] = digits[v%62]
+		v /= 62
+	}
+	return string(b[:])
+}
+
+// String returns r masked.
+func (r Redacted) String() string {
+	s := string(r)
+	var b strings.Builder
+	prefix := redactedPrefix(s)
+	b.WriteString(prefix)
+	s = s[len(prefix):]
+	n := 0
+	if len(s) >= redactMinLen {
+		n = min(4, len(s)/10)
+	}
+	b.WriteString(s[:n])
+	b.WriteString("…")
+	b.WriteString(s[len(s)-n:])
+	b.WriteString("#h:")
+	b.WriteString(r.Fingerprint())
+	return b.String()
+}
+
+// Format implements fmt.Formatter, every verb formats r masked like %s, or %q for %q.
+func (r Redacted) Format(f fmt.State, verb rune) {
+	if verb != 'q' {
+		verb = 's'
+	}
+	fmt.Fprintf(f, fmt.FormatString(f, verb), r.String())
+}
+
+// LogValue implements slog.LogValuer, r is logged masked.
+func (r Redacted) LogValue() slog.Value {
+	return slog.StringValue(r.String())
+}
+
+// MarshalText implements encoding.TextMarshaler, r is marshaled masked.
+func (r Redacted) MarshalText() ([]byte, error) {
+	return []byte(r.String()), nil
+}
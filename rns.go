@@ -0,0 +1,197 @@
+// Use of this source code is governed by the CC0 1.0
+// license that can be found in the LICENSE file or here:
+// http://creativecommons.org/publicdomain/zero/1.0/
+
+package base
+
+import (
+	"fmt"
+	"math/big"
+	"math/bits"
+)
+
+// RNS is a residue number system defined by a set of pairwise coprime moduli.
+//
+// An integer x is represented by its residues x mod m for every modulus m, integers in the range [0, M) where M is the product of all moduli
+// have a unique representation. Addition, subtraction and multiplication work independently on every residue.
+type RNS struct {
+	moduli []uint64
+	prod   *big.Int
+	// inv[i][j] is the inverse of moduli[j] modulo moduli[i] for j < i, used by the mixed radix conversion.
+	inv [][]uint64
+}
+
+// NewRNS returns a residue number system using moduli, every modulus must be at least 2 and all moduli must be pairwise coprime.
+func NewRNS(moduli ...uint64) (*RNS, error) {
+	if len(moduli) == 0 {
+		return nil, fmt.Errorf("Illegal empty set of moduli.")
+	}
+	r := &RNS{
+		moduli: append([]uint64(nil), moduli...),
+		prod:   big.NewInt(1),
+		inv:    make([][]uint64, len(moduli)),
+	}
+	a, b, g := new(big.Int), new(big.Int), new(big.Int)
+	for i, m := range r.moduli {
+		if m < 2 {
+			return nil, fmt.Errorf("Illegal modulus %d.", m)
+		}
+		r.inv[i] = make([]uint64, i)
+		for j := 0; j < i; j++ {
+			a.SetUint64(r.moduli[j])
+			b.SetUint64(m)
+			if g.GCD(nil, nil, a, b).Cmp(big.NewInt(1)) != 0 {
+				return nil, fmt.Errorf("Illegal moduli %d and %d, not coprime.", r.moduli[j], m)
+			}
+			r.inv[i][j] = g.ModInverse(a, b).Uint64()
+		}
+		r.prod.Mul(r.prod, a.SetUint64(m))
+	}
+	return r, nil
+}
+
+// Moduli returns the moduli of r.
+func (r *RNS) Moduli() []uint64 {
+	return append([]uint64(nil), r.moduli...)
+}
+
+// Range returns M, the product of all moduli of r. Every integer in the range [0, M) has a unique representation in r.
+func (r *RNS) Range() *big.Int {
+	return new(big.Int).Set(r.prod)
+}
+
+// FromInt returns the residues of x, negative integers are reduced to their representative in the range [0, M).
+func (r *RNS) FromInt(x *big.Int) []uint64 {
+	res := make([]uint64, len(r.moduli))
+	m, t := new(big.Int), new(big.Int)
+	for i, mod := range r.moduli {
+		res[i] = t.Mod(x, m.SetUint64(mod)).Uint64()
+	}
+	return res
+}
+
+// ToInt returns the integer in the range [0, M) represented by the residues x.
+//
+// The integer is reconstructed with the Chinese remainder theorem using mixed radix conversion which only needs arithmetic modulo the individual moduli.
+func (r *RNS) ToInt(x []uint64) (*big.Int, error) {
+	v, err := r.MixedRadix(x)
+	if err != nil {
+		return nil, err
+	}
+	// x = v[0] + v[1]*m[0] + v[2]*m[0]*m[1] + ...
+	n := new(big.Int)
+	t := new(big.Int)
+	for i := len(v) - 1; i >= 0; i-- {
+		n.Mul(n, t.SetUint64(r.moduli[i]))
+		n.Add(n, t.SetUint64(v[i]))
+	}
+	return n, nil
+}
+
+// MixedRadix returns the mixed radix digits v of the residues x, where the represented integer is v[0] + v[1]*m[0] + v[2]*m[0]*m[1] + ...
+// and every digit v[i] is less than m[i].
+func (r *RNS) MixedRadix(x []uint64) ([]uint64, error) {
+	if err := r.check(x); err != nil {
+		return nil, err
+	}
+	v := make([]uint64, len(x))
+	for i, m := range r.moduli {
+		t := x[i]
+		for j := 0; j < i; j++ {
+			t = mulMod(subMod(t, v[j]%m, m), r.inv[i][j], m)
+		}
+		v[i] = t
+	}
+	return v, nil
+}
+
+// Add returns the residues of the sum of x and y modulo M.
+func (r *RNS) Add(x, y []uint64) ([]uint64, error) {
+	return r.apply(x, y, func(a, b, m uint64) uint64 {
+		s, c := bits.Add64(a, b, 0)
+		if c != 0 || s >= m {
+			s -= m
+		}
+		return s
+	})
+}
+
+// Sub returns the residues of the difference of x and y modulo M.
+func (r *RNS) Sub(x, y []uint64) ([]uint64, error) {
+	return r.apply(x, y, subMod)
+}
+
+// Mul returns the residues of the product of x and y modulo M.
+func (r *RNS) Mul(x, y []uint64) ([]uint64, error) {
+	return r.apply(x, y, mulMod)
+}
+
+// Format returns every residue of x encoded with the digits of a.
+func (r *RNS) Format(x []uint64, a *Alphabet) ([][]byte, error) {
+	if err := r.check(x); err != nil {
+		return nil, err
+	}
+	res := make([][]byte, len(x))
+	t := new(big.Int)
+	for i, v := range x {
+		res[i], _ = a.EncodeInt(t.SetUint64(v))
+	}
+	return res, nil
+}
+
+// Parse returns the residues encoded with the digits of a in u, the inverse of Format.
+func (r *RNS) Parse(u [][]byte, a *Alphabet) ([]uint64, error) {
+	if len(u) != len(r.moduli) {
+		return nil, fmt.Errorf("Illegal number of residues %d, expected %d.", len(u), len(r.moduli))
+	}
+	x := make([]uint64, len(u))
+	for i, s := range u {
+		v, err := a.DecodeInt(s)
+		if err != nil {
+			return nil, err
+		}
+		if !v.IsUint64() || v.Uint64() >= r.moduli[i] {
+			return nil, fmt.Errorf("Residue %s out of range for modulus %d.", s, r.moduli[i])
+		}
+		x[i] = v.Uint64()
+	}
+	return x, nil
+}
+
+func (r *RNS) apply(x, y []uint64, f func(a, b, m uint64) uint64) ([]uint64, error) {
+	if err := r.check(x); err != nil {
+		return nil, err
+	}
+	if err := r.check(y); err != nil {
+		return nil, err
+	}
+	res := make([]uint64, len(x))
+	for i, m := range r.moduli {
+		res[i] = f(x[i], y[i], m)
+	}
+	return res, nil
+}
+
+func (r *RNS) check(x []uint64) error {
+	if len(x) != len(r.moduli) {
+		return fmt.Errorf("Illegal number of residues %d, expected %d.", len(x), len(r.moduli))
+	}
+	for i, v := range x {
+		if v >= r.moduli[i] {
+			return fmt.Errorf("Residue %d out of range for modulus %d.", v, r.moduli[i])
+		}
+	}
+	return nil
+}
+
+func subMod(a, b, m uint64) uint64 {
+	if a >= b {
+		return a - b
+	}
+	return m - (b - a)
+}
+
+func mulMod(a, b, m uint64) uint64 {
+	hi, lo := bits.Mul64(a, b)
+	return bits.Rem64(hi, lo, m)
+}
@@ -0,0 +1,111 @@
+package base_test
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/7i/base"
+)
+
+func TestNewRNS(t *testing.T) {
+	for _, m := range [][]uint64{{}, {1, 3}, {6, 9}, {3, 5, 10}} {
+		if _, err := base.NewRNS(m...); err == nil {
+			t.Errorf("NewRNS(%v) succeeded, expected an error.", m)
+		}
+	}
+}
+
+func TestRNSConversion(t *testing.T) {
+	// Large primes close to 2^64 exercise the 128 bit intermediate products
+	r, err := base.NewRNS(3, 5, 7, 1<<61-1, 18446744073709551557)
+	if err != nil {
+		t.Fatal(err)
+	}
+	m := r.Range()
+	exp := new(big.Int).Mul(big.NewInt(105), new(big.Int).SetUint64(1<<61-1))
+	exp.Mul(exp, new(big.Int).SetUint64(18446744073709551557))
+	if m.Cmp(exp) != 0 {
+		t.Fatalf("Range got: %v expected: %v.", m, exp)
+	}
+
+	vals := []*big.Int{big.NewInt(0), big.NewInt(1), big.NewInt(104), new(big.Int).Sub(m, big.NewInt(1)), new(big.Int).Rsh(m, 3), new(big.Int).SetBytes(decodedRnd[:20])}
+	for _, x := range vals {
+		x = new(big.Int).Mod(x, m)
+		res, err := r.ToInt(r.FromInt(x))
+		if err != nil || res.Cmp(x) != 0 {
+			t.Errorf("ToInt(FromInt(%v)) got: %v %v.", x, res, err)
+		}
+	}
+
+	// Negative integers map to their representative in [0, M)
+	res, _ := r.ToInt(r.FromInt(big.NewInt(-1)))
+	if res.Cmp(new(big.Int).Sub(m, big.NewInt(1))) != 0 {
+		t.Errorf("ToInt(FromInt(-1)) got: %v expected M-1.", res)
+	}
+
+	if _, err := r.ToInt([]uint64{3, 0, 0, 0, 0}); err == nil {
+		t.Errorf("ToInt accepted a residue out of range.")
+	}
+	if _, err := r.ToInt([]uint64{0}); err == nil {
+		t.Errorf("ToInt accepted too few residues.")
+	}
+}
+
+func TestRNSArithmetic(t *testing.T) {
+	r, err := base.NewRNS(251, 253, 255, 256, 1<<63-25)
+	if err != nil {
+		t.Fatal(err)
+	}
+	m := r.Range()
+	x := new(big.Int).SetBytes(decodedRnd[:12])
+	y := new(big.Int).SetBytes(decodedFF[:10])
+	rx, ry := r.FromInt(x), r.FromInt(y)
+
+	ops := []struct {
+		name string
+		f    func(a, b []uint64) ([]uint64, error)
+		exp  *big.Int
+	}{
+		{"Add", r.Add, new(big.Int).Add(x, y)},
+		{"Sub", r.Sub, new(big.Int).Sub(x, y)},
+		{"Sub", r.Sub, new(big.Int).Sub(y, x)},
+		{"Mul", r.Mul, new(big.Int).Mul(x, y)},
+	}
+	for i, op := range ops {
+		a, b := rx, ry
+		if i == 2 {
+			a, b = ry, rx
+		}
+		z, err := op.f(a, b)
+		if err != nil {
+			t.Fatal(err)
+		}
+		res, _ := r.ToInt(z)
+		if exp := op.exp.Mod(op.exp, m); res.Cmp(exp) != 0 {
+			t.Errorf("%s got: %v expected: %v.", op.name, res, exp)
+		}
+	}
+}
+
+func TestRNSFormat(t *testing.T) {
+	r, err := base.NewRNS(7, 36, 1297)
+	if err != nil {
+		t.Fatal(err)
+	}
+	a, _ := base.Digits(36)
+	x := r.FromInt(big.NewInt(1294))
+	s, err := r.Format(x, a)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(s[0]) != "6" || string(s[1]) != "y" || string(s[2]) != "zy" {
+		t.Errorf("Format got: %s expected: [6 y zy].", s)
+	}
+	p, err := r.Parse(s, a)
+	if err != nil || p[0] != x[0] || p[1] != x[1] || p[2] != x[2] {
+		t.Errorf("Parse got: %v %v expected: %v.", p, err, x)
+	}
+	if _, err := r.Parse([][]byte{[]byte("7"), []byte("0"), []byte("0")}, a); err == nil {
+		t.Errorf("Parse accepted a residue out of range.")
+	}
+}
@@ -0,0 +1,242 @@
+// Use of this source code is governed by the CC0 1.0
+// license that can be found in the LICENSE file or here:
+// http://creativecommons.org/publicdomain/zero/1.0/
+
+package base
+
+import (
+	"bufio"
+	"errors"
+	"io"
+	"math"
+	"sort"
+	"strings"
+)
+
+// DefaultScanEncodings are the encodings a Scanner looks for if its Encodings are not set.
+var DefaultScanEncodings = []string{"bech32", "base58check", "crockfordcheck", "16", "crockford", "58btc", "62"}
+
+// Finding is a possible secret found by a Scanner.
+type Finding struct {
+	File string
+	// Line and Column are the 1-based line number and byte column of Text, Offset is the byte offset of Text in the input.
+	Line, Column, Offset int
+	Text                 string
+	// Encoding is the name of the matching encoding, "bech32" or "bech32m" for bech32 strings.
+	Encoding string
+	// Entropy is the Shannon entropy of the digit values of Text in bits per digit. Score is Entropy relative to the maximum for Text,
+	// the bits per symbol of the alphabet or, for shorter tokens, log2 of the number of digits.
+	Entropy, Score float64
+	// Verified reports that Text has a valid checksum of 30 or more bits. The 5 bit check symbol of CrockfordCheck does not verify Text,
+	// it matches about one in 37 random strings.
+	Verified bool
+}
+
+// Scanner finds possible secrets, like keys and tokens encoded with one of the alphabets of this package, in text.
+//
+// A candidate is a token of an encoding, as found by FindAll, of at least MinLen characters. Candidates of checksummed encodings are only
+// reported with a valid checksum, those with a checksum of 30 or more bits, Base58Check and bech32, regardless of their Score.
+// All other candidates need a Score of at least MinScore, random data scores close to 1 while words and numbers score lower.
+// Of overlapping candidates verified ones are preferred, then longer ones, then those with a higher Score, which favors the smallest
+// alphabet that fits the token, and then those of an earlier encoding.
+type Scanner struct {
+	// Encodings are the names of the encodings to look for, see Lookup and Regexp. The name "bech32" selects bech32 and bech32m strings.
+	// Nil means DefaultScanEncodings.
+	Encodings []string
+	// MinLen is the minimum length of a candidate, 0 means 20.
+	MinLen int
+	// MinScore is the minimum Score of a candidate, 0 means 0.85.
+	MinScore float64
+}
+
+// scanRule is a resolved encoding of a Scanner.
+type scanRule struct {
+	name string
+	// enc is nil for bech32.
+	enc Encoding
+	// checkBits is the size of the checksum of enc, 0 if enc has none. Candidates need a valid checksum,
+	// with verifiedBits or more they are Verified.
+	checkBits int
+	alphabet  *Alphabet
+}
+
+// verifiedBits is the minimum size of a checksum that verifies a candidate.
+const verifiedBits = 30
+
+func (s *Scanner) rules() ([]scanRule, error) {
+	names := s.Encodings
+	if names == nil {
+		names = DefaultScanEncodings
+	}
+	var r []scanRule
+	for _, name := range names {
+		if name == "bech32" {
+			r = append(r, scanRule{name: name, checkBits: 30, alphabet: Bech32Alphabet})
+			continue
+		}
+		enc, err := Lookup(name)
+		if err != nil {
+			return nil, err
+		}
+		syn, err := syntaxOf(enc)
+		if err != nil {
+			return nil, err
+		}
+		rule := scanRule{name: name, enc: enc, alphabet: syn.alphabet}
+		switch enc.(type) {
+		case crockfordCheck:
+			rule.checkBits = 5
+		case base58Check:
+			rule.checkBits = 32
+		}
+		r = append(r, rule)
+	}
+	return r, nil
+}
+
+// Scan returns the findings in the text read from r, name is used as the File of the findings.
+func (s *Scanner) Scan(r io.Reader, name string) ([]Finding, error) {
+	rules, err := s.rules()
+	if err != nil {
+		return nil, err
+	}
+	var res []Finding
+	br := bufio.NewReader(r)
+	for n, offset := 1, 0; ; n++ {
+		line, err := br.ReadString('\n')
+		if err != nil && !errors.Is(err, io.EOF) {
+			return res, err
+		}
+		for _, f := range s.scanLine(strings.TrimRight(line, "\r\n"), rules) {
+			f.File, f.Line, f.Column, f.Offset = name, n, f.Offset+1, offset+f.Offset
+			res = append(res, f)
+		}
+		if err != nil {
+			return res, nil
+		}
+		offset += len(line)
+	}
+}
+
+// scanLine returns the findings in line with Offset set to the offset in line.
+func (s *Scanner) scanLine(line string, rules []scanRule) []Finding {
+	minLen, minScore := s.MinLen, s.MinScore
+	if minLen <= 0 {
+		minLen = 20
+	}
+	if minScore <= 0 {
+		minScore = 0.85
+	}
+	type candidate struct {
+		Finding
+		rule int
+	}
+	var cs []candidate
+	for i, rule := range rules {
+		var tokens []Token
+		if rule.enc == nil {
+			tokens = findBech32(line)
+		} else {
+			tokens, _ = FindAll(line, rule.enc)
+		}
+		for _, t := range tokens {
+			if len(t.Text) < minLen {
+				continue
+			}
+			f := Finding{Offset: t.Offset, Text: t.Text, Encoding: rule.name, Verified: rule.checkBits >= verifiedBits}
+			digits := t.Text
+			if rule.enc == nil {
+				f.Encoding = string(t.Data)
+				digits = digits[strings.LastIndexByte(digits, '1')+1:]
+			}
+			f.Entropy, f.Score = entropy(digits, rule.alphabet)
+			if !f.Verified && f.Score < minScore {
+				continue
+			}
+			cs = append(cs, candidate{f, i})
+		}
+	}
+	sort.SliceStable(cs, func(i, j int) bool {
+		a, b := cs[i], cs[j]
+		if a.Verified != b.Verified {
+			return a.Verified
+		}
+		if len(a.Text) != len(b.Text) {
+			return len(a.Text) > len(b.Text)
+		}
+		if a.Score != b.Score {
+			return a.Score > b.Score
+		}
+		return a.rule < b.rule
+	})
+	var r []Finding
+	taken := make([]bool, len(line))
+next:
+	for _, c := range cs {
+		end := c.Offset + len(c.Text)
+		for i := c.Offset; i < end; i++ {
+			if taken[i] {
+				continue next
+			}
+		}
+		for i := c.Offset; i < end; i++ {
+			taken[i] = true
+		}
+		r = append(r, c.Finding)
+	}
+	sort.Slice(r, func(i, j int) bool { return r[i].Offset < r[j].Offset })
+	return r
+}
+
+// findBech32 returns the alphanumeric words of line that are valid bech32 or bech32m strings, Data is the name of the variant.
+func findBech32(line string) []Token {
+	var r []Token
+	for i := 0; i < len(line); {
+		for i < len(line) && !isAlnum(line[i]) {
+			i++
+		}
+		start := i
+		for i < len(line) && isAlnum(line[i]) {
+			i++
+		}
+		word := line[start:i]
+		if len(word) < 8 {
+			continue
+		}
+		if _, _, v, err := DecodeBech32(word, 0); err == nil {
+			name := "bech32"
+			if v == Bech32m {
+				name = "bech32m"
+			}
+			r = append(r, Token{start, word, []byte(name)})
+		}
+	}
+	return r
+}
+
+func isAlnum(c byte) bool {
+	return '0' <= c && c <= '9' || 'a' <= c && c <= 'z' || 'A' <= c && c <= 'Z'
+}
+
+// entropy returns the Shannon entropy of the digit values of the characters of s in a, ignoring other characters, in bits per digit
+// and the entropy relative to the maximum for the number of digits in s.
+func entropy(s string, a *Alphabet) (h, score float64) {
+	var counts [256]int
+	n := 0
+	for i := 0; i < len(s); i++ {
+		if v := a.Index(s[i]); v >= 0 {
+			counts[v]++
+			n++
+		}
+	}
+	if n < 2 {
+		return 0, 0
+	}
+	for _, c := range counts {
+		if c > 0 {
+			p := float64(c) / float64(n)
+			h -= p * math.Log2(p)
+		}
+	}
+	return h, h / min(math.Log2(float64(a.Len())), math.Log2(float64(n)))
+}
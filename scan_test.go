@@ -0,0 +1,58 @@
+package base_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/7i/base"
+)
+
+func TestScanner(t *testing.T) {
+	text := "config:\n" +
+		"  token: 4eC39HqLyjWDarjtT1zdp7dcXq2\r\n" +
+		"  name: ThisIsACamelCaseIdentifierName internationalization 12345678901234567890\n" +
+		"sha1 da39a3ee5e6b4b0d3255bfef95601890afd80709 addr 16UwLL9Risc3QfPqBUvKofHmBQ7wMtjvM\n" +
+		"bc1qw508d6qejxtdg4y5r3zarvary0c5xw7kv8f3t4 16UwLL9Risc3QfPqBUvKofHmBQ7wMtjvN"
+	var s base.Scanner
+	fs, err := s.Scan(strings.NewReader(text), "app.yaml")
+	if err != nil {
+		t.Fatalf("Scan got error: %v.", err)
+	}
+	exp := []struct {
+		line, column, offset int
+		text, encoding       string
+		verified             bool
+	}{
+		{2, 10, 17, "4eC39HqLyjWDarjtT1zdp7dcXq2", "62", false},
+		{4, 6, 132, "da39a3ee5e6b4b0d3255bfef95601890afd80709", "16", false},
+		{4, 52, 178, "16UwLL9Risc3QfPqBUvKofHmBQ7wMtjvM", "base58check", true},
+		{5, 1, 212, "bc1qw508d6qejxtdg4y5r3zarvary0c5xw7kv8f3t4", "bech32", true},
+		{5, 44, 255, "16UwLL9Risc3QfPqBUvKofHmBQ7wMtjvN", "58btc", false},
+	}
+	if len(fs) != len(exp) {
+		t.Fatalf("Scan got: %+v expected %d findings.", fs, len(exp))
+	}
+	for i, e := range exp {
+		f := fs[i]
+		if f.File != "app.yaml" || f.Line != e.line || f.Column != e.column || f.Offset != e.offset || f.Text != e.text ||
+			f.Encoding != e.encoding || f.Verified != e.verified || f.Score < 0.85 || f.Score > 1 {
+			t.Errorf("Scan finding %d got: %+v expected: %+v.", i, f, e)
+		}
+		if text[f.Offset:f.Offset+len(f.Text)] != f.Text {
+			t.Errorf("Scan finding %d has a wrong offset %d.", i, f.Offset)
+		}
+	}
+
+	s = base.Scanner{Encodings: []string{"crockfordcheck"}, MinLen: 4}
+	fs, _ = s.Scan(strings.NewReader("ids 7ZQM-4KXW-9A2D-H and 7ZQM-4KXW-9A2D-Q"), "")
+	if len(fs) != 1 || fs[0].Text != "7ZQM-4KXW-9A2D-H" || fs[0].Verified {
+		t.Errorf("Scan of Crockford check IDs got: %+v.", fs)
+	}
+
+	for _, encs := range [][]string{{"nosuchencoding"}, {"base64"}} {
+		s = base.Scanner{Encodings: encs}
+		if _, err := s.Scan(strings.NewReader(""), ""); err == nil {
+			t.Errorf("Scan with encodings %v succeeded, expected an error.", encs)
+		}
+	}
+}
@@ -0,0 +1,145 @@
+// Use of this source code is governed by the CC0 1.0
+// license that can be found in the LICENSE file or here:
+// http://creativecommons.org/publicdomain/zero/1.0/
+
+package base
+
+import (
+	"fmt"
+	"math"
+	"runtime"
+)
+
+// The secret functions encode and decode like Encode and Decode but are meant for private keys and other secrets.
+// They do not use math/big, whose internal buffers keep copies of the data, write into buffers provided by the caller
+// and wipe all scratch memory before returning. Unlike the result of Encode, which is a slice of a larger allocation,
+// the caller controls the lifetime of every copy and can Wipe it when done.
+
+// Wipe overwrites b with zeros.
+func Wipe(b []byte) {
+	clear(b)
+	runtime.KeepAlive(b)
+}
+
+// MaxEncodedLen returns the maximum length of the base b encoding of n bytes, a sufficient size of the dst buffer of EncodeSecret.
+func MaxEncodedLen(n, b int) int {
+	if b < 2 || b > len(digits) || n <= 0 {
+		return 0
+	}
+	return int(math.Ceil(float64(n)*8/math.Log2(float64(b)))) + 1
+}
+
+// MaxDecodedLen returns the maximum number of bytes encoded by n digits of base b, a sufficient size of the dst buffer of DecodeSecret.
+func MaxDecodedLen(n, b int) int {
+	if b < 2 || b > len(digits) || n <= 0 {
+		return 0
+	}
+	return int(math.Ceil(float64(n)*math.Log2(float64(b))/8)) + 1
+}
+
+// EncodeSecret writes the base b encoding of src to dst and returns the number of digits written.
+//
+// The result is the same as Encode, including the removal of leading null bytes. The rest of dst is zeroed.
+// If dst is too small EncodeSecret wipes dst and returns an error, see MaxEncodedLen.
+func EncodeSecret(dst, src []byte, b int) (n int, err error) {
+	return encodeSecret(dst, src, b, nil)
+}
+
+// encodeSecret is EncodeSecret, calling wiped with its scratch buffer after wiping it if wiped is not nil.
+func encodeSecret(dst, src []byte, b int, wiped func(scratch []byte)) (n int, err error) {
+	if b < 2 || b > len(digits) {
+		return 0, fmt.Errorf("Illegal Encode base.")
+	}
+	start := 0
+	for start < len(src) && src[start] == 0 {
+		start++
+	}
+	// The number is divided by b in place, so it is copied to a scratch buffer that is wiped before returning.
+	num := make([]byte, len(src)-start)
+	copy(num, src[start:])
+	defer func() {
+		Wipe(num)
+		if wiped != nil {
+			wiped(num)
+		}
+	}()
+
+	// The digits are written from the end of dst, least significant digit first.
+	i := len(dst)
+	for lead := 0; lead < len(num); {
+		rem := 0
+		for j := lead; j < len(num); j++ {
+			cur := rem<<8 | int(num[j])
+			num[j] = byte(cur / b)
+			rem = cur % b
+		}
+		if i == 0 {
+			Wipe(dst)
+			return 0, fmt.Errorf("Destination buffer too small for base %d encoding.", b)
+		}
+		i--
+		dst[i] = digits[rem]
+		for lead < len(num) && num[lead] == 0 {
+			lead++
+		}
+	}
+	n = copy(dst, dst[i:])
+	Wipe(dst[n:])
+	return n, nil
+}
+
+// DecodeSecret writes the data of the base b encoded src to dst and returns the number of bytes written.
+//
+// The result is the same as Decode, including the removal of leading null bytes. The rest of dst is zeroed.
+// If src is not a valid encoding or dst is too small DecodeSecret wipes dst and returns an error, see MaxDecodedLen.
+func DecodeSecret(dst, src []byte, b int) (n int, err error) {
+	if b < 2 || b > len(digits) {
+		return 0, fmt.Errorf("Illegal Decode base.")
+	}
+	// The number is accumulated right aligned at the end of dst, n is its length in bytes.
+	end := len(dst)
+	for _, c := range src {
+		v := digitValue(c, b)
+		if v < 0 {
+			Wipe(dst)
+			return 0, fmt.Errorf("Illegal characters in base %d decoding.", b)
+		}
+		carry := v
+		for j := end - 1; j >= end-n; j-- {
+			x := int(dst[j])*b + carry
+			dst[j] = byte(x)
+			carry = x >> 8
+		}
+		for carry > 0 {
+			if n == end {
+				Wipe(dst)
+				return 0, fmt.Errorf("Destination buffer too small for base %d decoding.", b)
+			}
+			n++
+			dst[end-n] = byte(carry)
+			carry >>= 8
+		}
+	}
+	copy(dst, dst[end-n:])
+	Wipe(dst[n:])
+	return n, nil
+}
+
+// digitValue returns the value of the digit c in base b like Decode or -1 if c is not a digit of base b.
+func digitValue(c byte, b int) int {
+	v := b
+	switch {
+	case '0' <= c && c <= '9':
+		v = int(c - '0')
+	case 'a' <= c && c <= 'z':
+		v = int(c-'a') + 10
+	case 'A' <= c && c <= 'Z' && b <= 36:
+		v = int(c-'A') + 10
+	case 'A' <= c && c <= 'Z':
+		v = int(c-'A') + 36
+	}
+	if v >= b {
+		return -1
+	}
+	return v
+}
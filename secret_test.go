@@ -0,0 +1,97 @@
+package base_test
+
+import (
+	"bytes"
+	"math/rand"
+	"testing"
+
+	"github.com/7i/base"
+)
+
+func TestSecret(t *testing.T) {
+	var scratch [][]byte
+	wiped := func(s []byte) { scratch = append(scratch, s) }
+
+	rnd := rand.New(rand.NewSource(1))
+	for b := 2; b <= 62; b++ {
+		for _, l := range []int{0, 1, 2, 31, 64} {
+			src := make([]byte, l)
+			rnd.Read(src)
+			if l > 1 {
+				src[0] = 0 // a leading null byte is removed like by Encode
+			}
+			exp, _ := base.Encode(src, b)
+
+			// Fill dst with garbage to check that the unused tail is zeroed.
+			dst := bytes.Repeat([]byte{0xaa}, base.MaxEncodedLen(l, b)+3)
+			n, err := base.EncodeSecretWiped(dst, src, b, wiped)
+			if err != nil || string(dst[:n]) != string(exp) {
+				t.Errorf("EncodeSecret(%x, %d) got: %s %v expected: %s.", src, b, dst[:n], err, exp)
+			}
+			if !allZero(dst[n:]) {
+				t.Errorf("EncodeSecret(%x, %d) left data after the result: %x.", src, b, dst[n:])
+			}
+
+			dec, _ := base.Decode(exp, b)
+			out := bytes.Repeat([]byte{0xaa}, base.MaxDecodedLen(len(exp), b))
+			n, err = base.DecodeSecret(out, exp, b)
+			if err != nil || !bytes.Equal(out[:n], dec) {
+				t.Errorf("DecodeSecret(%s, %d) got: %x %v expected: %x.", exp, b, out[:n], err, dec)
+			}
+			if !allZero(out[n:]) {
+				t.Errorf("DecodeSecret(%s, %d) left data after the result: %x.", exp, b, out[n:])
+			}
+		}
+	}
+
+	if len(scratch) == 0 {
+		t.Fatalf("EncodeSecret did not report its scratch buffers.")
+	}
+	for _, s := range scratch {
+		if !allZero(s) {
+			t.Errorf("EncodeSecret did not wipe its scratch buffer: %x.", s)
+		}
+	}
+
+	// Case insensitive like Decode for bases up to 36
+	out := make([]byte, 4)
+	if n, err := base.DecodeSecret(out, []byte("FF"), 16); err != nil || !bytes.Equal(out[:n], []byte{0xff}) {
+		t.Errorf("DecodeSecret(FF, 16) got: %x %v.", out[:n], err)
+	}
+}
+
+func TestSecretErrors(t *testing.T) {
+	wiped := false
+	src := []byte{0xde, 0xad, 0xbe, 0xef}
+	dst := []byte("xxxxx")
+	if _, err := base.EncodeSecretWiped(dst, src, 2, func(s []byte) { wiped = allZero(s) }); err == nil || !allZero(dst) || !wiped {
+		t.Errorf("EncodeSecret into a small buffer got: %v %x %v.", err, dst, wiped)
+	}
+
+	out := []byte{1, 2, 3}
+	if _, err := base.DecodeSecret(out, []byte("deadbeef"), 16); err == nil || !allZero(out) {
+		t.Errorf("DecodeSecret into a small buffer got: %v %x.", err, out)
+	}
+	out = []byte{1, 2, 3}
+	if _, err := base.DecodeSecret(out, []byte("1g"), 16); err == nil || !allZero(out) {
+		t.Errorf("DecodeSecret of an illegal character got: %v %x.", err, out)
+	}
+	if _, err := base.EncodeSecret(out, src, 63); err == nil {
+		t.Errorf("EncodeSecret accepted base 63.")
+	}
+
+	k := []byte("secret")
+	base.Wipe(k)
+	if !allZero(k) {
+		t.Errorf("Wipe got: %q.", k)
+	}
+}
+
+func allZero(b []byte) bool {
+	for _, c := range b {
+		if c != 0 {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,51 @@
+// Use of this source code is governed by the CC0 1.0
+// license that can be found in the LICENSE file or here:
+// http://creativecommons.org/publicdomain/zero/1.0/
+
+package base
+
+import (
+	"fmt"
+	"math/big"
+)
+
+// ShortUUIDAlphabet is the base57 alphabet of the Python shortuuid package, the digits and letters without the easily confused 0, 1, I, O and l.
+var ShortUUIDAlphabet = mustAlphabet("23456789ABCDEFGHJKLMNPQRSTUVWXYZabcdefghijkmnopqrstuvwxyz")
+
+// shortUUIDLength is the number of base57 digits needed for 128 bits.
+const shortUUIDLength = 22
+
+// EncodeShortUUID returns u encoded like shortuuid.encode in the Python shortuuid package version 1.0 and later,
+// the UUID as a big endian integer in 22 base57 digits with the most significant digit first.
+func EncodeShortUUID(u UUID) string {
+	r, _ := ShortUUIDAlphabet.EncodeFixed(new(big.Int).SetBytes(u[:]), shortUUIDLength)
+	return string(r)
+}
+
+// DecodeShortUUID returns the UUID encoded in s by EncodeShortUUID or the Python shortuuid package.
+//
+// Like shortuuid.decode, shorter strings are accepted and treated as if padded with leading zero digits.
+func DecodeShortUUID(s string) (UUID, error) {
+	if len(s) == 0 || len(s) > shortUUIDLength {
+		return UUID{}, fmt.Errorf("Illegal short UUID length %d.", len(s))
+	}
+	n, err := ShortUUIDAlphabet.DecodeInt([]byte(s))
+	if err != nil {
+		return UUID{}, err
+	}
+	if n.BitLen() > 128 {
+		return UUID{}, fmt.Errorf("Short UUID %q out of range.", s)
+	}
+	var u UUID
+	n.FillBytes(u[:])
+	return u, nil
+}
+
+// NewShortUUID returns a random version 4 UUID encoded by EncodeShortUUID, like shortuuid.uuid() without arguments.
+func NewShortUUID() (string, error) {
+	u, err := NewUUIDv4()
+	if err != nil {
+		return "", err
+	}
+	return EncodeShortUUID(u), nil
+}
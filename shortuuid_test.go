@@ -0,0 +1,56 @@
+package base_test
+
+import (
+	"testing"
+
+	"github.com/7i/base"
+)
+
+// Test vectors shared with the Python shortuuid package, the last two are the examples from its README:
+// shortuuid.uuid(name="example.com") and shortuuid.decode("CXc85b4rqinB7s5J52TRYb").
+var shortUUIDTests = []struct {
+	uuid, short string
+}{
+	{"00000000-0000-0000-0000-000000000000", "2222222222222222222222"},
+	{"00000000-0000-0000-0000-000000000001", "2222222222222222222223"},
+	{"ffffffff-ffff-ffff-ffff-ffffffffffff", "oZEq7ovRbLq6UnGMPwc8B5"},
+	{"6ba7b810-9dad-11d1-80b4-00c04fd430c8", "MAnkyno2VCnFzuVMWtxBda"},
+	{"01890a5d-ac96-774b-bcce-b302099a8057", "2HaXEbvYTEKu2nZ5P8B348"},
+	{"cfbff0d1-9375-5685-968c-48ce8b15ae17", "exu3DTbj2ncsn9tLdLWspw"},
+	{"3b1f8b40-222c-4a6e-b77e-779d5a94e21c", "CXc85b4rqinB7s5J52TRYb"},
+}
+
+func TestShortUUID(t *testing.T) {
+	if base.ShortUUIDAlphabet.Len() != 57 {
+		t.Errorf("ShortUUIDAlphabet has %d digits, expected 57.", base.ShortUUIDAlphabet.Len())
+	}
+	for _, test := range shortUUIDTests {
+		u, _ := base.ParseUUID(test.uuid)
+		if res := base.EncodeShortUUID(u); res != test.short {
+			t.Errorf("EncodeShortUUID(%s) got: %s expected: %s.", test.uuid, res, test.short)
+		}
+		d, err := base.DecodeShortUUID(test.short)
+		if err != nil || d != u {
+			t.Errorf("DecodeShortUUID(%s) got: %v %v expected: %s.", test.short, d, err, test.uuid)
+		}
+	}
+
+	// Missing leading zero digits are accepted
+	d, err := base.DecodeShortUUID("3")
+	if err != nil || d.String() != "00000000-0000-0000-0000-000000000001" {
+		t.Errorf("DecodeShortUUID(3) got: %v %v.", d, err)
+	}
+
+	s, err := base.NewShortUUID()
+	if err != nil || len(s) != 22 {
+		t.Errorf("NewShortUUID got: %q %v.", s, err)
+	}
+}
+
+func TestShortUUIDErrors(t *testing.T) {
+	for _, s := range []string{"", "oZEq7ovRbLq6UnGMPwc8B6", "zzzzzzzzzzzzzzzzzzzzzz", "2222222222222222222222l", "I"} {
+		if _, err := base.DecodeShortUUID(s); err == nil {
+			t.Errorf("DecodeShortUUID(%q) succeeded, expected an error.", s)
+		}
+	}
+}
@@ -0,0 +1,175 @@
+// Use of this source code is governed by the CC0 1.0
+// license that can be found in the LICENSE file or here:
+// http://creativecommons.org/publicdomain/zero/1.0/
+
+package base
+
+import (
+	"fmt"
+	"math"
+	"math/big"
+)
+
+// The sortable encodings map numbers to strings whose byte order matches the numeric order.
+// They all require a sorted Alphabet, see Alphabet.Sorted. Digits(b) is sorted for b up to 36.
+
+// EncodeSortableUint64 returns x as a fixed width string of a.Width(64) digits of a.
+func EncodeSortableUint64(x uint64, a *Alphabet) ([]byte, error) {
+	if !a.Sorted() {
+		return nil, fmt.Errorf("Illegal unsorted alphabet for sortable encoding.")
+	}
+	return a.EncodeFixed(new(big.Int).SetUint64(x), a.Width(64))
+}
+
+// DecodeSortableUint64 returns the integer encoded in u by EncodeSortableUint64.
+func DecodeSortableUint64(u []byte, a *Alphabet) (uint64, error) {
+	if len(u) != a.Width(64) {
+		return 0, fmt.Errorf("Illegal sortable encoding length %d, expected %d.", len(u), a.Width(64))
+	}
+	n, err := a.DecodeInt(u)
+	if err != nil {
+		return 0, err
+	}
+	if !n.IsUint64() {
+		return 0, fmt.Errorf("Sortable encoding out of range.")
+	}
+	return n.Uint64(), nil
+}
+
+// EncodeSortableInt64 returns x as a fixed width string of a.Width(64) digits of a, negative numbers sort before positive numbers.
+func EncodeSortableInt64(x int64, a *Alphabet) ([]byte, error) {
+	// Flipping the sign bit moves the negative numbers below the positive numbers.
+	return EncodeSortableUint64(uint64(x)^1<<63, a)
+}
+
+// DecodeSortableInt64 returns the integer encoded in u by EncodeSortableInt64.
+func DecodeSortableInt64(u []byte, a *Alphabet) (int64, error) {
+	x, err := DecodeSortableUint64(u, a)
+	return int64(x ^ 1<<63), err
+}
+
+// EncodeSortableFloat64 returns f as a fixed width string of a.Width(64) digits of a.
+//
+// The strings sort as -Inf < negative numbers < -0 < +0 < positive numbers < +Inf < NaN.
+// All NaN values are encoded as the same canonical NaN.
+func EncodeSortableFloat64(f float64, a *Alphabet) ([]byte, error) {
+	var x uint64
+	switch b := math.Float64bits(f); {
+	case math.IsNaN(f):
+		x = math.MaxUint64
+	case b>>63 == 1:
+		// Negative numbers are inverted so that larger magnitudes sort first.
+		x = ^b
+	default:
+		x = b | 1<<63
+	}
+	return EncodeSortableUint64(x, a)
+}
+
+// DecodeSortableFloat64 returns the float encoded in u by EncodeSortableFloat64.
+func DecodeSortableFloat64(u []byte, a *Alphabet) (float64, error) {
+	x, err := DecodeSortableUint64(u, a)
+	if err != nil {
+		return 0, err
+	}
+	switch {
+	case x == math.MaxUint64:
+		return math.NaN(), nil
+	case x>>63 == 1:
+		return math.Float64frombits(x &^ (1 << 63)), nil
+	}
+	return math.Float64frombits(^x), nil
+}
+
+// EncodeSortableBigInt returns x as a length prefixed string of digits of a that sorts in numeric order.
+//
+// The string starts with the last digit of a for non negative numbers and the first digit of a for negative numbers,
+// followed by the number of magnitude digits as a.Width(32) digits and the magnitude digits.
+// For negative numbers the length and the magnitude digits are complemented so that larger magnitudes sort first.
+func EncodeSortableBigInt(x *big.Int, a *Alphabet) ([]byte, error) {
+	if !a.Sorted() {
+		return nil, fmt.Errorf("Illegal unsorted alphabet for sortable encoding.")
+	}
+	var mag []byte
+	if x.Sign() != 0 {
+		mag, _ = a.EncodeInt(new(big.Int).Abs(x))
+	}
+	if uint64(len(mag)) > math.MaxUint32 {
+		return nil, fmt.Errorf("Integer too large for sortable encoding.")
+	}
+	l, _ := a.EncodeFixed(big.NewInt(int64(len(mag))), a.Width(32))
+
+	r := make([]byte, 0, 1+len(l)+len(mag))
+	if x.Sign() >= 0 {
+		r = append(r, a.chars[len(a.chars)-1])
+		r = append(r, l...)
+		return append(r, mag...), nil
+	}
+	r = append(r, a.chars[0])
+	r = append(r, l...)
+	r = append(r, mag...)
+	a.complement(r[1:])
+	return r, nil
+}
+
+// DecodeSortableBigInt returns the integer encoded in u by EncodeSortableBigInt.
+func DecodeSortableBigInt(u []byte, a *Alphabet) (*big.Int, error) {
+	w := a.Width(32)
+	if len(u) < 1+w {
+		return nil, fmt.Errorf("Illegal sortable encoding length %d.", len(u))
+	}
+	neg := false
+	switch u[0] {
+	case a.chars[0]:
+		neg = true
+		u = append([]byte(nil), u[1:]...)
+		if err := a.complementChecked(u); err != nil {
+			return nil, err
+		}
+	case a.chars[len(a.chars)-1]:
+		u = u[1:]
+	default:
+		return nil, fmt.Errorf("Illegal sortable encoding sign %q.", u[0])
+	}
+	l, err := a.DecodeInt(u[:w])
+	if err != nil {
+		return nil, err
+	}
+	if !l.IsInt64() || l.Int64() != int64(len(u)-w) {
+		return nil, fmt.Errorf("Sortable encoding length mismatch.")
+	}
+	mag := u[w:]
+	if len(mag) > 0 && a.dec[mag[0]] == 0 {
+		return nil, fmt.Errorf("Illegal leading zero in sortable encoding.")
+	}
+	x, err := a.DecodeInt(mag)
+	if err != nil {
+		return nil, err
+	}
+	if neg {
+		if x.Sign() == 0 {
+			return nil, fmt.Errorf("Illegal negative zero in sortable encoding.")
+		}
+		x.Neg(x)
+	}
+	return x, nil
+}
+
+// complement replaces every digit d of u with k-1-d where k is the base of a.
+func (a *Alphabet) complement(u []byte) {
+	k := len(a.chars)
+	for i, c := range u {
+		u[i] = a.chars[k-1-int(a.dec[c])]
+	}
+}
+
+// complementChecked is like complement but returns an error if u contains characters outside of a.
+func (a *Alphabet) complementChecked(u []byte) error {
+	for _, c := range u {
+		if a.dec[c] < 0 {
+			return fmt.Errorf("Illegal character %q in alphabet decoding.", c)
+		}
+	}
+	a.complement(u)
+	return nil
+}
@@ -0,0 +1,122 @@
+package base_test
+
+import (
+	"bytes"
+	"math"
+	"math/big"
+	"math/rand"
+	"sort"
+	"testing"
+
+	"github.com/7i/base"
+)
+
+func sortableAlphabets(t *testing.T) []*base.Alphabet {
+	b2, _ := base.Digits(2)
+	b10, _ := base.Digits(10)
+	b36, _ := base.Digits(36)
+	b64, err := base.NewAlphabet("-0123456789ABCDEFGHIJKLMNOPQRSTUVWXYZ_abcdefghijklmnopqrstuvwxyz")
+	if err != nil {
+		t.Fatal(err)
+	}
+	return []*base.Alphabet{b2, b10, b36, b64}
+}
+
+func TestSortableInt64(t *testing.T) {
+	vals := []int64{math.MinInt64, math.MinInt64 + 1, -1 << 40, -1000, -1, 0, 1, 2, 1000, 1 << 40, math.MaxInt64 - 1, math.MaxInt64}
+	r := rand.New(rand.NewSource(1))
+	for i := 0; i < 200; i++ {
+		vals = append(vals, int64(r.Uint64()))
+	}
+	sort.Slice(vals, func(i, j int) bool { return vals[i] < vals[j] })
+	for _, a := range sortableAlphabets(t) {
+		var prev []byte
+		for _, v := range vals {
+			u, err := base.EncodeSortableInt64(v, a)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if len(u) != a.Width(64) {
+				t.Errorf("EncodeSortableInt64(%d) got length %d expected %d.", v, len(u), a.Width(64))
+			}
+			if prev != nil && bytes.Compare(prev, u) > 0 {
+				t.Errorf("EncodeSortableInt64(%d) = %s sorts before %s.", v, u, prev)
+			}
+			prev = u
+			d, err := base.DecodeSortableInt64(u, a)
+			if err != nil || d != v {
+				t.Errorf("DecodeSortableInt64(%s) got: %d %v expected: %d.", u, d, err, v)
+			}
+		}
+	}
+}
+
+func TestSortableFloat64(t *testing.T) {
+	vals := []float64{math.Inf(-1), -math.MaxFloat64, -1e10, -1, -math.SmallestNonzeroFloat64, math.Copysign(0, -1), 0, math.SmallestNonzeroFloat64, 0.5, 1, 1e300, math.MaxFloat64, math.Inf(1), math.NaN()}
+	for _, a := range sortableAlphabets(t) {
+		var prev []byte
+		for _, v := range vals {
+			u, err := base.EncodeSortableFloat64(v, a)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if prev != nil && bytes.Compare(prev, u) >= 0 {
+				t.Errorf("EncodeSortableFloat64(%v) = %s does not sort after %s.", v, u, prev)
+			}
+			prev = u
+			d, err := base.DecodeSortableFloat64(u, a)
+			if err != nil || math.Float64bits(d) != math.Float64bits(v) && !(math.IsNaN(d) && math.IsNaN(v)) {
+				t.Errorf("DecodeSortableFloat64(%s) got: %v %v expected: %v.", u, d, err, v)
+			}
+		}
+		// Every NaN shares the canonical encoding
+		n1, _ := base.EncodeSortableFloat64(math.Float64frombits(0xfff8000000000001), a)
+		n2, _ := base.EncodeSortableFloat64(math.NaN(), a)
+		if !bytes.Equal(n1, n2) {
+			t.Errorf("EncodeSortableFloat64 NaN encodings differ: %s %s.", n1, n2)
+		}
+	}
+}
+
+func TestSortableBigInt(t *testing.T) {
+	huge := new(big.Int).SetBytes(decodedFF)
+	vals := []*big.Int{new(big.Int).Neg(huge), big.NewInt(-1 << 62), big.NewInt(-256), big.NewInt(-255), big.NewInt(-1), big.NewInt(0), big.NewInt(1), big.NewInt(9), big.NewInt(10), big.NewInt(1 << 62), new(big.Int).SetBytes(decodedRnd), huge}
+	for _, a := range sortableAlphabets(t) {
+		var prev []byte
+		for _, v := range vals {
+			u, err := base.EncodeSortableBigInt(v, a)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if prev != nil && bytes.Compare(prev, u) >= 0 {
+				t.Errorf("EncodeSortableBigInt(%v) = %s does not sort after %s.", v, u, prev)
+			}
+			prev = u
+			d, err := base.DecodeSortableBigInt(u, a)
+			if err != nil || d.Cmp(v) != 0 {
+				t.Errorf("DecodeSortableBigInt(%s) got: %v %v expected: %v.", u, d, err, v)
+			}
+		}
+	}
+}
+
+func TestSortableErrors(t *testing.T) {
+	b62, _ := base.Digits(62)
+	if _, err := base.EncodeSortableInt64(1, b62); err == nil {
+		t.Errorf("EncodeSortableInt64 accepted an unsorted alphabet.")
+	}
+	if _, err := base.EncodeSortableBigInt(big.NewInt(1), b62); err == nil {
+		t.Errorf("EncodeSortableBigInt accepted an unsorted alphabet.")
+	}
+	b10, _ := base.Digits(10)
+	for _, s := range []string{"", "123", "99999999999999999999"} {
+		if _, err := base.DecodeSortableUint64([]byte(s), b10); err == nil {
+			t.Errorf("DecodeSortableUint64(%s) succeeded, expected an error.", s)
+		}
+	}
+	for _, s := range []string{"5", "90000000001", "90000000002", "90000000001a", "90000000001" + "0", "0" + "9999999999" + "9"} {
+		if _, err := base.DecodeSortableBigInt([]byte(s), b10); err == nil {
+			t.Errorf("DecodeSortableBigInt(%s) succeeded, expected an error.", s)
+		}
+	}
+}
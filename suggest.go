@@ -0,0 +1,136 @@
+// Use of this source code is governed by the CC0 1.0
+// license that can be found in the LICENSE file or here:
+// http://creativecommons.org/publicdomain/zero/1.0/
+
+package base
+
+import (
+	"fmt"
+	"sort"
+)
+
+// Suggestion is a likely intended value of a mistyped code returned by Suggest.
+type Suggestion struct {
+	Text string
+	// Data is the result of decoding Text.
+	Data []byte
+	// Edits is the number of edits from the input to Text and Cost their combined unlikeliness, lower is more likely.
+	Edits int
+	Cost  float64
+}
+
+// The costs of the edits of Suggest. A confusable character is the most common typo in copied codes, a missed or extra character the least common.
+const (
+	costConfusable   = 1
+	costTranspose    = 2
+	costInsertDelete = 3
+)
+
+// confusables holds pairs of characters that are easily mistaken for each other when reading or typing a code.
+// The other case of a letter is confusable too.
+var confusables = [][2]byte{
+	{'0', 'O'}, {'0', 'o'}, {'0', 'D'}, {'0', 'Q'}, {'O', 'Q'}, {'O', 'D'},
+	{'1', 'l'}, {'1', 'I'}, {'1', 'i'}, {'1', 'L'}, {'1', '7'}, {'l', 'I'}, {'l', 'i'},
+	{'2', 'Z'}, {'2', 'z'}, {'5', 'S'}, {'5', 's'}, {'6', 'G'}, {'6', 'b'}, {'8', 'B'}, {'9', 'g'}, {'9', 'q'},
+	{'U', 'V'}, {'u', 'v'}, {'n', 'm'}, {'n', 'h'}, {'r', 'n'}, {'c', 'e'}, {'v', 'y'},
+}
+
+// Suggest returns the strings enc decodes without error that are at most maxEdits edits, 0 to 2, away from input, ranked by the likeliness of the edits.
+// It is meant for checksummed encodings like CrockfordCheck and Base58Check, where only few of the edited strings are valid.
+// If input decodes it is the only suggestion.
+//
+// An edit is the substitution of a confusable character, e.g. O and 0, l and 1 or S and 5 or another case of a letter,
+// the transposition of two adjacent characters or, at most once, the deletion of a character or the insertion of a character of enc.
+// Suggestions that decode to the same data as a more likely one are left out. The work grows with the square of len(input) for two edits,
+// so the length of untrusted input should be limited. See Regexp for the supported encodings.
+func Suggest(input string, enc Encoding, maxEdits int) ([]Suggestion, error) {
+	if maxEdits < 0 || maxEdits > 2 {
+		return nil, fmt.Errorf("Illegal number of edits %d.", maxEdits)
+	}
+	syn, err := syntaxOf(enc)
+	if err != nil {
+		return nil, err
+	}
+	if d, err := enc.Decode([]byte(input)); err == nil {
+		return []Suggestion{{Text: input, Data: d}}, nil
+	}
+	chars := syn.digits + syn.check
+	var subst [256][]byte
+	for _, p := range confusables {
+		subst[p[0]] = append(subst[p[0]], p[1])
+		subst[p[1]] = append(subst[p[1]], p[0])
+	}
+	for c := 'a'; c <= 'z'; c++ {
+		subst[c] = append(subst[c], byte(c-'a'+'A'))
+		subst[c-'a'+'A'] = append(subst[c-'a'+'A'], byte(c))
+	}
+
+	type state struct {
+		edits int
+		cost  float64
+	}
+	seen := map[string]state{input: {}}
+	frontier := []string{input}
+	for e := 1; e <= maxEdits; e++ {
+		var next []string
+		for _, s := range frontier {
+			cost := seen[s].cost
+			add := func(t string, c float64) {
+				if old, ok := seen[t]; ok && old.cost <= cost+c {
+					return
+				}
+				seen[t] = state{e, cost + c}
+				next = append(next, t)
+			}
+			for i := 0; i < len(s); i++ {
+				for _, c := range subst[s[i]] {
+					add(s[:i]+string(c)+s[i+1:], costConfusable)
+				}
+				if i+1 < len(s) && s[i] != s[i+1] {
+					add(s[:i]+s[i+1:i+2]+s[i:i+1]+s[i+2:], costTranspose)
+				}
+			}
+			if len(s) != len(input) {
+				continue
+			}
+			for i := 0; i <= len(s); i++ {
+				if i < len(s) {
+					add(s[:i]+s[i+1:], costInsertDelete)
+				}
+				for j := 0; j < len(chars); j++ {
+					add(s[:i]+chars[j:j+1]+s[i:], costInsertDelete)
+				}
+			}
+		}
+		frontier = next
+	}
+
+	var r []Suggestion
+	for t, st := range seen {
+		if t == input {
+			continue
+		}
+		if d, err := enc.Decode([]byte(t)); err == nil {
+			r = append(r, Suggestion{Text: t, Data: d, Edits: st.edits, Cost: st.cost})
+		}
+	}
+	sort.Slice(r, func(i, j int) bool {
+		if r[i].Cost != r[j].Cost {
+			return r[i].Cost < r[j].Cost
+		}
+		if r[i].Edits != r[j].Edits {
+			return r[i].Edits < r[j].Edits
+		}
+		return r[i].Text < r[j].Text
+	})
+	data := map[string]bool{}
+	n := 0
+	for _, s := range r {
+		if !data[string(s.Data)] {
+			data[string(s.Data)] = true
+			r[n] = s
+			n++
+		}
+	}
+	return r[:n], nil
+}
@@ -0,0 +1,64 @@
+package base_test
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/7i/base"
+)
+
+func TestSuggest(t *testing.T) {
+	const addr = "16UwLL9Risc3QfPqBUvKofHmBQ7wMtjvM"
+	data, _ := base.Base58Check.Decode([]byte(addr))
+	tests := []struct {
+		input    string
+		maxEdits int
+		edits    int
+		cost     float64
+	}{
+		{addr, 1, 0, 0},
+		{"l6UwLL9Risc3QfPqBUvKofHmBQ7wMtjvM", 1, 1, 1},
+		{"16UwLL9Risc3QfPqBUvKofHmBQ7wMtjvm", 1, 1, 1},
+		{"16UwLL9Rise3QfPqBUvKofHmBQ7wMtjvM", 1, 1, 1},
+		{"16UwLL9Rics3QfPqBUvKofHmBQ7wMtjvM", 1, 1, 2},
+		{"16UwLL9Risc3QfPqBUvKofHmBQ7wMtjv", 1, 1, 3},
+		{"16UwLL9Risc3QfPqBUvKofHmBQ77wMtjvM", 1, 1, 3},
+		{"l6UwLL9Risc3QfPqBUvKofHmBQ7wMtvjM", 2, 2, 3},
+	}
+	for _, test := range tests {
+		res, err := base.Suggest(test.input, base.Base58Check, test.maxEdits)
+		if err != nil {
+			t.Errorf("Suggest(%s, %d) got error: %v.", test.input, test.maxEdits, err)
+			continue
+		}
+		if len(res) == 0 || res[0].Text != addr || !bytes.Equal(res[0].Data, data) || res[0].Edits != test.edits || res[0].Cost != test.cost {
+			t.Errorf("Suggest(%s, %d) got: %+v expected %s with %d edits of cost %v first.", test.input, test.maxEdits, res, addr, test.edits, test.cost)
+		}
+	}
+
+	// The check symbol of CrockfordCheck only catches some errors, every valid candidate is suggested.
+	res, err := base.Suggest("16DJ", base.CrockfordCheck, 1)
+	if err != nil || len(res) == 0 {
+		t.Fatalf("Suggest(16DJ) got: %v %v.", res, err)
+	}
+	found := false
+	for i, s := range res {
+		if _, err := base.CrockfordCheck.Decode([]byte(s.Text)); err != nil {
+			t.Errorf("Suggest(16DJ) suggested invalid %s.", s.Text)
+		}
+		if i > 0 && s.Cost < res[i-1].Cost {
+			t.Errorf("Suggest(16DJ) is not ranked by cost: %v.", res)
+		}
+		found = found || s.Text == "16JD"
+	}
+	if !found {
+		t.Errorf("Suggest(16DJ) got: %v expected 16JD.", res)
+	}
+
+	if _, err := base.Suggest(addr, base.Base58Check, 3); err == nil {
+		t.Errorf("Suggest with 3 edits succeeded, expected an error.")
+	}
+	if _, err := base.Suggest("abc", multibase(t, 'm'), 1); err == nil {
+		t.Errorf("Suggest of an RFC 4648 encoding succeeded, expected an error.")
+	}
+}
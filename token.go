@@ -0,0 +1,294 @@
+// Use of this source code is governed by the CC0 1.0
+// license that can be found in the LICENSE file or here:
+// http://creativecommons.org/publicdomain/zero/1.0/
+
+package base
+
+import (
+	"bufio"
+	"fmt"
+	"regexp"
+	"strings"
+	"sync"
+)
+
+// tokenSyntax describes the strings accepted by the Decode method of an encoding.
+type tokenSyntax struct {
+	// alphabet gives the digit values of the characters.
+	alphabet *Alphabet
+	// digits holds every character accepted as a digit, including other cases and aliases.
+	digits string
+	// separators holds characters that may appear between digits and are ignored by Decode.
+	separators string
+	// check holds the additional characters of a trailing check symbol.
+	check string
+	// group is the number of characters every valid encoding is a multiple of, 0 or 1 for any length.
+	group int
+	// minLen is the minimum number of digits and check symbols of a valid encoding.
+	minLen int
+}
+
+// syntaxer is implemented by the encodings supported by Regexp, FindAll and SplitTokens.
+type syntaxer interface {
+	syntax() tokenSyntax
+}
+
+func (a *Alphabet) syntax() tokenSyntax {
+	var d []byte
+	for c := 0; c < 128; c++ {
+		if a.dec[c] >= 0 {
+			d = append(d, byte(c))
+		}
+	}
+	return tokenSyntax{alphabet: a, digits: string(d), minLen: 1}
+}
+
+func (l leadingZeros) syntax() tokenSyntax {
+	return l.a.syntax()
+}
+
+func (hexEncoding) syntax() tokenSyntax {
+	s := mustAlphabet("0123456789abcdef").CaseInsensitive().syntax()
+	s.group, s.minLen = 2, 2
+	return s
+}
+
+func (nix32) syntax() tokenSyntax {
+	return nix32Alphabet.syntax()
+}
+
+func (gitBase85) syntax() tokenSyntax {
+	s := RFC1924.syntax()
+	s.group, s.minLen = 5, 5
+	return s
+}
+
+func (crockfordCheck) syntax() tokenSyntax {
+	s := Crockford.syntax()
+	s.separators = "-"
+	s.check = crockfordCheckSymbols + "u"
+	s.minLen = 2
+	return s
+}
+
+func (base58Check) syntax() tokenSyntax {
+	return Base58BTC.(leadingZeros).syntax()
+}
+
+func syntaxOf(enc Encoding) (tokenSyntax, error) {
+	if s, ok := enc.(syntaxer); ok {
+		return s.syntax(), nil
+	}
+	return tokenSyntax{}, fmt.Errorf("Unsupported encoding %T for token matching.", enc)
+}
+
+// Regexp returns a regular expression matching the strings of minLen to maxLen characters accepted by enc, or of any length from minLen if maxLen is 0.
+//
+// The lengths count digits and check symbols but not separators. Separators, like the hyphens of CrockfordCheck, may appear between two characters.
+// A trailing check symbol may use characters that are not digits, encodings of a fixed group size only match multiples of the group size,
+// and minLen is raised to the shortest valid encoding. The expression is not anchored, see FindAll for matching tokens in free text.
+//
+// Regexp supports the alphabets, the multibase encodings except the RFC 4648 ones, and the named encodings of this package.
+func Regexp(enc Encoding, minLen, maxLen int) (*regexp.Regexp, error) {
+	s, err := syntaxOf(enc)
+	if err != nil {
+		return nil, err
+	}
+	return s.regexp(minLen, maxLen)
+}
+
+func (s tokenSyntax) regexp(minLen, maxLen int) (*regexp.Regexp, error) {
+	minLen = max(minLen, s.minLen, 1)
+	if maxLen > 0 && maxLen < minLen {
+		return nil, fmt.Errorf("Illegal token lengths %d to %d.", minLen, maxLen)
+	}
+	sep := ""
+	if s.separators != "" {
+		sep = charClass(s.separators) + "?"
+	}
+	unit := charClass(s.digits)
+	var tail string
+	if s.check != "" {
+		tail = sep + charClass(s.digits+s.check)
+		minLen--
+		maxLen = max(maxLen-1, 0)
+	}
+	if g := s.group; g > 1 {
+		unit = fmt.Sprintf("(?:%s{%d})", unit, g)
+		minLen = (minLen + g - 1) / g
+		maxLen /= g
+		if maxLen > 0 && maxLen < minLen {
+			return nil, fmt.Errorf("No multiple of %d in token lengths.", g)
+		}
+	}
+
+	var b strings.Builder
+	b.WriteString(unit)
+	if sep != "" {
+		b.WriteString("(?:" + sep + unit + ")")
+	} else {
+		b.WriteString(unit)
+	}
+	switch lo, hi := minLen-1, maxLen-1; {
+	case maxLen == 0:
+		fmt.Fprintf(&b, "{%d,}", lo)
+	case lo == hi:
+		fmt.Fprintf(&b, "{%d}", lo)
+	default:
+		fmt.Fprintf(&b, "{%d,%d}", lo, hi)
+	}
+	b.WriteString(tail)
+	return regexp.Compile(b.String())
+}
+
+// charClass returns a regular expression character class matching the characters of chars.
+func charClass(chars string) string {
+	var b strings.Builder
+	b.WriteByte('[')
+	for i := 0; i < len(chars); i++ {
+		c := chars[i]
+		if !('0' <= c && c <= '9' || 'a' <= c && c <= 'z' || 'A' <= c && c <= 'Z') {
+			b.WriteByte('\\')
+		}
+		b.WriteByte(c)
+	}
+	b.WriteByte(']')
+	return b.String()
+}
+
+// Token is a valid encoding found in text by FindAll.
+type Token struct {
+	// Offset is the byte offset of Text in the searched text.
+	Offset int
+	Text   string
+	// Data is the result of decoding Text.
+	Data []byte
+}
+
+// FindAll returns every token of text that enc decodes without error, so checksums are verified for checksummed encodings like CrockfordCheck.
+//
+// A token is a maximal run of digits, check symbols and separators of enc not starting or ending with a separator. Runs that do not match
+// the syntax of Regexp as a whole or fail to decode are skipped, so a token is never a part of a longer word. See Regexp for the supported encodings.
+func FindAll(text string, enc Encoding) ([]Token, error) {
+	m, err := newTokenMatcher(enc)
+	if err != nil {
+		return nil, err
+	}
+	var r []Token
+	for i := 0; i < len(text); {
+		start, end, next := m.run(text, i)
+		if start == end {
+			break
+		}
+		if d, ok := m.decode(text[start:end]); ok {
+			r = append(r, Token{start, text[start:end], d})
+		}
+		i = next
+	}
+	return r, nil
+}
+
+// SplitTokens returns a bufio.SplitFunc for a bufio.Scanner that returns the tokens FindAll finds, without the decoded data.
+// Tokens longer than the buffer of the Scanner end the scan with bufio.ErrTooLong.
+// For unsupported encodings the SplitFunc returns the error of Regexp.
+func SplitTokens(enc Encoding) bufio.SplitFunc {
+	m, err := newTokenMatcher(enc)
+	return func(data []byte, atEOF bool) (advance int, token []byte, e error) {
+		if err != nil {
+			return 0, nil, err
+		}
+		s := string(data)
+		for i := 0; i < len(s); {
+			start, end, next := m.run(s, i)
+			if start == end {
+				return len(s), nil, nil
+			}
+			if next == len(s) && !atEOF {
+				// The run may continue in the next read, keep it.
+				return start, nil, nil
+			}
+			if _, ok := m.decode(s[start:end]); ok {
+				return next, data[start:end], nil
+			}
+			i = next
+		}
+		return len(s), nil, nil
+	}
+}
+
+// tokenMatcher finds the runs of token characters of an encoding and validates them.
+type tokenMatcher struct {
+	enc    Encoding
+	syntax tokenSyntax
+	re     *regexp.Regexp
+	// chars and seps are lookup tables of the run characters and the separators.
+	chars, seps [256]bool
+}
+
+var matcherCache sync.Map // matcherKey to *tokenMatcher
+
+// matcherKey returns the key of enc in matcherCache, false for encodings that are not cached.
+// Only the comparable encodings of this package are cached, alphabets by value since Digits returns a new one for every call.
+func matcherKey(enc Encoding) (any, bool) {
+	switch e := enc.(type) {
+	case *Alphabet:
+		return *e, true
+	case leadingZeros, hexEncoding, nix32, gitBase85, crockfordCheck, base58Check:
+		return e, true
+	}
+	return nil, false
+}
+
+func newTokenMatcher(enc Encoding) (*tokenMatcher, error) {
+	s, err := syntaxOf(enc)
+	if err != nil {
+		return nil, err
+	}
+	key, cache := matcherKey(enc)
+	if cache {
+		if m, ok := matcherCache.Load(key); ok {
+			return m.(*tokenMatcher), nil
+		}
+	}
+	re, err := s.regexp(0, 0)
+	if err != nil {
+		return nil, err
+	}
+	m := &tokenMatcher{enc: enc, syntax: s, re: regexp.MustCompile("^(?:" + re.String() + ")$")}
+	for _, c := range []byte(s.digits + s.check + s.separators) {
+		m.chars[c] = true
+	}
+	for _, c := range []byte(s.separators) {
+		m.seps[c] = true
+	}
+	if cache {
+		matcherCache.Store(key, m)
+	}
+	return m, nil
+}
+
+// run returns the bounds of the next run of token characters in s at or after i, without leading and trailing separators,
+// and the end of the run including trailing separators. It returns start == end if there is none.
+func (m *tokenMatcher) run(s string, i int) (start, end, next int) {
+	for i < len(s) && (!m.chars[s[i]] || m.seps[s[i]]) {
+		i++
+	}
+	start = i
+	for i < len(s) && m.chars[s[i]] {
+		i++
+	}
+	end, next = i, i
+	for end > start && m.seps[s[end-1]] {
+		end--
+	}
+	return start, end, next
+}
+
+// decode returns the data of the token t if it matches the syntax of the encoding and decodes.
+func (m *tokenMatcher) decode(t string) ([]byte, bool) {
+	if !m.re.MatchString(t) {
+		return nil, false
+	}
+	d, err := m.enc.Decode([]byte(t))
+	return d, err == nil
+}
@@ -0,0 +1,152 @@
+package base_test
+
+import (
+	"bufio"
+	"bytes"
+	"reflect"
+	"regexp"
+	"strings"
+	"testing"
+	"testing/iotest"
+
+	"github.com/7i/base"
+)
+
+func TestRegexp(t *testing.T) {
+	hex, _ := base.Digits(16)
+	tests := []struct {
+		enc              base.Encoding
+		minLen, maxLen   int
+		matches, rejects []string
+	}{
+		{hex, 4, 8, []string{"00ff", "DEADbeef"}, []string{"fff", "123456789", "00fg"}},
+		{base.RFC1924, 5, 5, []string{"a!#$%", "{|}~`", "^_-;<"}, []string{"a:b\"c", "ab cd", "abcd"}},
+		{base.CrockfordCheck, 0, 0, []string{"16JD", "1-6-J-D", "10*", "14u"}, []string{"1", "*1", "16-", "1--6", "1*JD"}},
+		{base.GitBase85, 1, 10, []string{"abcde", "0123456789"}, []string{"abcd", "abcdef"}},
+		{multibase(t, 'f'), 0, 0, []string{"00", "aB01"}, []string{"0", "abc"}},
+		{base.Base58Check, 2, 4, []string{"1z", "zzzz"}, []string{"0z", "zzzzz", "Iz"}},
+	}
+	for _, test := range tests {
+		re, err := base.Regexp(test.enc, test.minLen, test.maxLen)
+		if err != nil {
+			t.Errorf("Regexp(%T, %d, %d) got error: %v.", test.enc, test.minLen, test.maxLen, err)
+			continue
+		}
+		re = regexp.MustCompile("^(?:" + re.String() + ")$")
+		for _, s := range test.matches {
+			if !re.MatchString(s) {
+				t.Errorf("Regexp(%T, %d, %d) %s does not match %q.", test.enc, test.minLen, test.maxLen, re, s)
+			}
+		}
+		for _, s := range test.rejects {
+			if re.MatchString(s) {
+				t.Errorf("Regexp(%T, %d, %d) %s matches %q.", test.enc, test.minLen, test.maxLen, re, s)
+			}
+		}
+	}
+
+	if _, err := base.Regexp(hex, 5, 4); err == nil {
+		t.Errorf("Regexp with maxLen < minLen succeeded, expected an error.")
+	}
+	if _, err := base.Regexp(base.GitBase85, 6, 9); err == nil {
+		t.Errorf("Regexp without a multiple of the group size succeeded, expected an error.")
+	}
+	if _, err := base.Regexp(multibase(t, 'u'), 1, 0); err == nil {
+		t.Errorf("Regexp of an RFC 4648 encoding succeeded, expected an error.")
+	}
+}
+
+func TestFindAll(t *testing.T) {
+	text := "order 16JD shipped, ref 16JE is a typo, see 1-6-J-D and X16JDX or (16jd)."
+	tokens, err := base.FindAll(text, base.CrockfordCheck)
+	if err != nil {
+		t.Fatalf("FindAll got error: %v.", err)
+	}
+	exp := []base.Token{
+		{Offset: 6, Text: "16JD", Data: []byte{0x04, 0xd2}},
+		{Offset: 44, Text: "1-6-J-D", Data: []byte{0x04, 0xd2}},
+		{Offset: 67, Text: "16jd", Data: []byte{0x04, 0xd2}},
+	}
+	if !reflect.DeepEqual(tokens, exp) {
+		t.Errorf("FindAll(%q) got: %v expected: %v.", text, tokens, exp)
+	}
+
+	text = "pay to 16UwLL9Risc3QfPqBUvKofHmBQ7wMtjvM, not 16UwLL9Risc3QfPqBUvKofHmBQ7wMtjvN"
+	tokens, _ = base.FindAll(text, base.Base58Check)
+	if len(tokens) != 1 || tokens[0].Offset != 7 || len(tokens[0].Data) != 21 {
+		t.Errorf("FindAll(%q) got: %v.", text, tokens)
+	}
+	if _, err := base.FindAll(text, multibase(t, 'm')); err == nil {
+		t.Errorf("FindAll of an RFC 4648 encoding succeeded, expected an error.")
+	}
+}
+
+func TestSplitTokens(t *testing.T) {
+	var text strings.Builder
+	var exp []string
+	for i := 0; i < 200; i++ {
+		tok := string(base.CrockfordCheck.Encode([]byte{byte(i), byte(i * 7)}))
+		text.WriteString("id " + tok + " and 16JE, ")
+		exp = append(exp, tok)
+	}
+	s := bufio.NewScanner(iotest.HalfReader(strings.NewReader(text.String())))
+	s.Buffer(make([]byte, 16), 64)
+	s.Split(base.SplitTokens(base.CrockfordCheck))
+	var got []string
+	for s.Scan() {
+		got = append(got, s.Text())
+	}
+	if err := s.Err(); err != nil {
+		t.Fatalf("Scan got error: %v.", err)
+	}
+	if !reflect.DeepEqual(got, exp) {
+		t.Errorf("SplitTokens got: %v expected: %v.", got, exp)
+	}
+
+	s = bufio.NewScanner(bytes.NewReader([]byte("abc")))
+	s.Split(base.SplitTokens(multibase(t, 'm')))
+	if s.Scan() || s.Err() == nil {
+		t.Errorf("SplitTokens of an RFC 4648 encoding got no error.")
+	}
+}
+
+func multibase(t *testing.T, prefix byte) base.Encoding {
+	e, err := base.MultibaseEncoding(prefix)
+	if err != nil {
+		t.Fatalf("MultibaseEncoding(%c) got error: %v.", prefix, err)
+	}
+	return e
+}
+
+// sliceEncoding is an Encoding with an unhashable dynamic type.
+type sliceEncoding []byte
+
+func (sliceEncoding) Encode(src []byte) []byte          { return src }
+func (sliceEncoding) Decode(src []byte) ([]byte, error) { return src, nil }
+
+// embeddedAlphabet promotes the methods of an Alphabet but is not comparable.
+type embeddedAlphabet struct {
+	*base.Alphabet
+	aliases []string
+}
+
+func TestTokenUnhashableEncoding(t *testing.T) {
+	enc := sliceEncoding{1}
+	if _, err := base.FindAll("abc", enc); err == nil {
+		t.Errorf("FindAll of an unsupported encoding succeeded, expected an error.")
+	}
+	if _, err := base.Regexp(enc, 1, 0); err == nil {
+		t.Errorf("Regexp of an unsupported encoding succeeded, expected an error.")
+	}
+	s := bufio.NewScanner(strings.NewReader("abc"))
+	s.Split(base.SplitTokens(enc))
+	if s.Scan() || s.Err() == nil {
+		t.Errorf("SplitTokens of an unsupported encoding got no error.")
+	}
+
+	hex, _ := base.Digits(16)
+	tokens, err := base.FindAll("xyz ff00 ghij", embeddedAlphabet{hex, nil})
+	if err != nil || len(tokens) != 1 || tokens[0].Text != "ff00" {
+		t.Errorf("FindAll of an embedded alphabet got: %v %v.", tokens, err)
+	}
+}
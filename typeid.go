@@ -0,0 +1,156 @@
+// Use of this source code is governed by the CC0 1.0
+// license that can be found in the LICENSE file or here:
+// http://creativecommons.org/publicdomain/zero/1.0/
+
+package base
+
+import (
+	"database/sql/driver"
+	"fmt"
+	"math/big"
+	"strings"
+)
+
+// Crockford is Douglas Crockford's base32 alphabet. Decoding is case insensitive and accepts I and L as 1 and O as 0.
+var Crockford = mustAlphabet("0123456789ABCDEFGHJKMNPQRSTVWXYZ").CaseInsensitive().withAliases("I1i1L1l1O0o0")
+
+// typeIDSuffix is the strict lower case Crockford alphabet of TypeID suffixes, without the aliases of Crockford.
+var typeIDSuffix = mustAlphabet("0123456789abcdefghjkmnpqrstvwxyz")
+
+const (
+	typeIDSuffixLength = 26
+	typeIDMaxPrefix    = 63
+)
+
+// TypeID is a type safe, K-sortable identifier like user_01h455vb4pex5vsknk084sn02q,
+// a type prefix and a UUID encoded as 26 lower case Crockford base32 characters.
+//
+// The zero TypeID has an empty prefix and the nil UUID. TypeID implements encoding.TextMarshaler,
+// encoding.TextUnmarshaler, sql.Scanner and driver.Valuer using its string form.
+type TypeID struct {
+	prefix string
+	uuid   UUID
+}
+
+// NewTypeID returns a TypeID with the given prefix and a new version 7 UUID, see NewUUIDv7.
+func NewTypeID(prefix string) (TypeID, error) {
+	if err := checkTypeIDPrefix(prefix); err != nil {
+		return TypeID{}, err
+	}
+	u, err := NewUUIDv7()
+	if err != nil {
+		return TypeID{}, err
+	}
+	return TypeID{prefix, u}, nil
+}
+
+// TypeIDFromUUID returns the TypeID with the given prefix and UUID.
+func TypeIDFromUUID(prefix string, u UUID) (TypeID, error) {
+	if err := checkTypeIDPrefix(prefix); err != nil {
+		return TypeID{}, err
+	}
+	return TypeID{prefix, u}, nil
+}
+
+// ParseTypeID returns the TypeID s, an optional prefix and underscore followed by the 26 character suffix.
+func ParseTypeID(s string) (TypeID, error) {
+	prefix, suffix := "", s
+	if i := strings.LastIndexByte(s, '_'); i >= 0 {
+		prefix, suffix = s[:i], s[i+1:]
+		if prefix == "" {
+			return TypeID{}, fmt.Errorf("Illegal TypeID %q with empty prefix.", s)
+		}
+	}
+	if err := checkTypeIDPrefix(prefix); err != nil {
+		return TypeID{}, err
+	}
+	if len(suffix) != typeIDSuffixLength {
+		return TypeID{}, fmt.Errorf("Illegal TypeID suffix length %d.", len(suffix))
+	}
+	// 26 characters hold 130 bits, the first character may only use 3 bits.
+	if suffix[0] > '7' {
+		return TypeID{}, fmt.Errorf("TypeID suffix %q out of range.", suffix)
+	}
+	n, err := typeIDSuffix.DecodeInt([]byte(suffix))
+	if err != nil {
+		return TypeID{}, err
+	}
+	t := TypeID{prefix: prefix}
+	n.FillBytes(t.uuid[:])
+	return t, nil
+}
+
+// Prefix returns the type prefix of t.
+func (t TypeID) Prefix() string {
+	return t.prefix
+}
+
+// UUID returns the UUID of t.
+func (t TypeID) UUID() UUID {
+	return t.uuid
+}
+
+// Suffix returns the 26 character Crockford base32 encoding of the UUID of t.
+func (t TypeID) Suffix() string {
+	s, _ := typeIDSuffix.EncodeFixed(new(big.Int).SetBytes(t.uuid[:]), typeIDSuffixLength)
+	return string(s)
+}
+
+// String returns t as prefix_suffix, or only the suffix if the prefix is empty.
+func (t TypeID) String() string {
+	if t.prefix == "" {
+		return t.Suffix()
+	}
+	return t.prefix + "_" + t.Suffix()
+}
+
+// MarshalText implements encoding.TextMarshaler.
+func (t TypeID) MarshalText() ([]byte, error) {
+	return []byte(t.String()), nil
+}
+
+// UnmarshalText implements encoding.TextUnmarshaler.
+func (t *TypeID) UnmarshalText(b []byte) error {
+	p, err := ParseTypeID(string(b))
+	if err != nil {
+		return err
+	}
+	*t = p
+	return nil
+}
+
+// Scan implements sql.Scanner for string and []byte values, a NULL value sets the zero TypeID.
+func (t *TypeID) Scan(src any) error {
+	switch v := src.(type) {
+	case nil:
+		*t = TypeID{}
+		return nil
+	case string:
+		return t.UnmarshalText([]byte(v))
+	case []byte:
+		return t.UnmarshalText(v)
+	}
+	return fmt.Errorf("Illegal TypeID source type %T.", src)
+}
+
+// Value implements driver.Valuer, t is stored as its string form.
+func (t TypeID) Value() (driver.Value, error) {
+	return t.String(), nil
+}
+
+// checkTypeIDPrefix returns an error unless prefix consists of at most 63 lower case letters and underscores,
+// not starting or ending with an underscore.
+func checkTypeIDPrefix(prefix string) error {
+	if len(prefix) > typeIDMaxPrefix {
+		return fmt.Errorf("Illegal TypeID prefix length %d.", len(prefix))
+	}
+	if strings.HasPrefix(prefix, "_") || strings.HasSuffix(prefix, "_") {
+		return fmt.Errorf("Illegal TypeID prefix %q.", prefix)
+	}
+	for i := 0; i < len(prefix); i++ {
+		if c := prefix[i]; (c < 'a' || c > 'z') && c != '_' {
+			return fmt.Errorf("Illegal TypeID prefix %q.", prefix)
+		}
+	}
+	return nil
+}
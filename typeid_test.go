@@ -0,0 +1,132 @@
+package base_test
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/7i/base"
+)
+
+// Valid and invalid examples from the TypeID specification
+var typeIDTests = []struct {
+	typeid, prefix, uuid string
+}{
+	{"00000000000000000000000000", "", "00000000-0000-0000-0000-000000000000"},
+	{"00000000000000000000000001", "", "00000000-0000-0000-0000-000000000001"},
+	{"0000000000000000000000000a", "", "00000000-0000-0000-0000-00000000000a"},
+	{"0000000000000000000000000g", "", "00000000-0000-0000-0000-000000000010"},
+	{"00000000000000000000000010", "", "00000000-0000-0000-0000-000000000020"},
+	{"7zzzzzzzzzzzzzzzzzzzzzzzzz", "", "ffffffff-ffff-ffff-ffff-ffffffffffff"},
+	{"prefix_0123456789abcdefghjkmnpqrs", "prefix", "0110c853-1d09-52d8-d73e-1194e95b5f19"},
+	{"prefix_01h455vb4pex5vsknk084sn02q", "prefix", "01890a5d-ac96-774b-bcce-b302099a8057"},
+	{"pre_fix_00000000000000000000000000", "pre_fix", "00000000-0000-0000-0000-000000000000"},
+}
+
+var invalidTypeIDs = []string{
+	"PREFIX_00000000000000000000000000",
+	"12345_00000000000000000000000000",
+	"pre.fix_00000000000000000000000000",
+	"préfix_00000000000000000000000000",
+	"  prefix_00000000000000000000000000",
+	"abcdefghijklmnopqrstuvwxyzabcdefghijklmnopqrstuvwxyzabcdefghijkl_00000000000000000000000000",
+	"_00000000000000000000000000",
+	"_",
+	"prefix_1234567890123456789012345",
+	"prefix_123456789012345678901234567",
+	"prefix_1234567890123456789012345 ",
+	"prefix_0123456789ABCDEFGHJKMNPQRS",
+	"prefix_123456789-123456789-123456",
+	"prefix_ooooooooooooooooooooooooo0",
+	"prefix_i23456789ooooooooooooooooo",
+	"prefix_8zzzzzzzzzzzzzzzzzzzzzzzzz",
+	"_prefix_00000000000000000000000000",
+	"prefix__00000000000000000000000000",
+}
+
+func TestTypeID(t *testing.T) {
+	for _, test := range typeIDTests {
+		id, err := base.ParseTypeID(test.typeid)
+		if err != nil {
+			t.Errorf("ParseTypeID(%q) got: %v.", test.typeid, err)
+			continue
+		}
+		if id.Prefix() != test.prefix || id.UUID().String() != test.uuid {
+			t.Errorf("ParseTypeID(%q) got: %q %v expected: %q %s.", test.typeid, id.Prefix(), id.UUID(), test.prefix, test.uuid)
+		}
+		u, _ := base.ParseUUID(test.uuid)
+		id, err = base.TypeIDFromUUID(test.prefix, u)
+		if err != nil || id.String() != test.typeid {
+			t.Errorf("TypeIDFromUUID(%q, %s) got: %s %v expected: %s.", test.prefix, test.uuid, id, err, test.typeid)
+		}
+	}
+	for _, s := range invalidTypeIDs {
+		if id, err := base.ParseTypeID(s); err == nil {
+			t.Errorf("ParseTypeID(%q) succeeded with %s, expected an error.", s, id)
+		}
+	}
+}
+
+func TestNewTypeID(t *testing.T) {
+	var prev string
+	for i := 0; i < 100; i++ {
+		id, err := base.NewTypeID("user")
+		if err != nil {
+			t.Fatal(err)
+		}
+		s := id.String()
+		if len(s) != 31 || s[:5] != "user_" || id.UUID().Version() != 7 {
+			t.Errorf("NewTypeID got malformed %s.", s)
+		}
+		if s <= prev {
+			t.Errorf("NewTypeID got %s after %s, expected increasing ids.", s, prev)
+		}
+		prev = s
+		p, err := base.ParseTypeID(s)
+		if err != nil || p != id {
+			t.Errorf("ParseTypeID(%s) got: %v %v.", s, p, err)
+		}
+	}
+	if _, err := base.NewTypeID("User"); err == nil {
+		t.Errorf("NewTypeID accepted an upper case prefix.")
+	}
+}
+
+func TestTypeIDMarshal(t *testing.T) {
+	id, _ := base.ParseTypeID("user_01h455vb4pex5vsknk084sn02q")
+	b, err := json.Marshal(map[string]base.TypeID{"id": id})
+	if err != nil || string(b) != `{"id":"user_01h455vb4pex5vsknk084sn02q"}` {
+		t.Errorf("json.Marshal got: %s %v.", b, err)
+	}
+	var m map[string]base.TypeID
+	if err := json.Unmarshal(b, &m); err != nil || m["id"] != id {
+		t.Errorf("json.Unmarshal got: %v %v.", m, err)
+	}
+
+	v, err := id.Value()
+	if err != nil || v != "user_01h455vb4pex5vsknk084sn02q" {
+		t.Errorf("Value got: %v %v.", v, err)
+	}
+	var s base.TypeID
+	for _, src := range []any{"user_01h455vb4pex5vsknk084sn02q", []byte("user_01h455vb4pex5vsknk084sn02q")} {
+		if err := s.Scan(src); err != nil || s != id {
+			t.Errorf("Scan(%v) got: %v %v.", src, s, err)
+		}
+	}
+	if err := s.Scan(42); err == nil {
+		t.Errorf("Scan accepted an integer.")
+	}
+	if err := s.Scan(nil); err != nil || s != (base.TypeID{}) {
+		t.Errorf("Scan(nil) got: %v %v expected the zero TypeID.", s, err)
+	}
+}
+
+func TestCrockford(t *testing.T) {
+	a, err := base.Crockford.DecodeInt([]byte("1lIoO0zZ"))
+	b, _ := base.Crockford.DecodeInt([]byte("111000ZZ"))
+	if err != nil || a.Cmp(b) != 0 {
+		t.Errorf("Crockford did not accept aliases, got: %v %v expected: %v.", a, err, b)
+	}
+	if _, err := base.Crockford.DecodeInt([]byte("U")); err == nil {
+		t.Errorf("Crockford accepted U.")
+	}
+}
@@ -0,0 +1,127 @@
+// Use of this source code is governed by the CC0 1.0
+// license that can be found in the LICENSE file or here:
+// http://creativecommons.org/publicdomain/zero/1.0/
+
+package base
+
+import (
+	"crypto/rand"
+	"encoding/binary"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"sync"
+	"time"
+)
+
+// UUID is a 128 bit universally unique identifier as defined in RFC 9562.
+type UUID [16]byte
+
+// NewUUIDv4 returns a random version 4 UUID.
+func NewUUIDv4() (UUID, error) {
+	var u UUID
+	if _, err := rand.Read(u[:]); err != nil {
+		return UUID{}, err
+	}
+	u[6] = u[6]&0x0f | 0x40
+	u[8] = u[8]&0x3f | 0x80
+	return u, nil
+}
+
+// uuidV7 holds the last version 7 UUID to keep generated UUIDs strictly increasing.
+var uuidV7 struct {
+	sync.Mutex
+	ms   int64
+	last UUID
+}
+
+// NewUUIDv7 returns a version 7 UUID, a 48 bit Unix timestamp in milliseconds followed by 74 random bits.
+//
+// UUIDs returned by NewUUIDv7 are strictly increasing. Within the same millisecond, or if the clock moves backwards,
+// the random bits of the previous UUID are incremented as a counter as described in RFC 9562 section 6.2.
+func NewUUIDv7() (UUID, error) {
+	return newUUIDv7(rand.Reader, time.Now().UnixMilli())
+}
+
+func newUUIDv7(r io.Reader, ms int64) (UUID, error) {
+	uuidV7.Lock()
+	defer uuidV7.Unlock()
+
+	var u UUID
+	if ms <= uuidV7.ms {
+		ms = uuidV7.ms
+		u = uuidV7.last
+		// rand_a is the low 12 bits of bytes 6 and 7, rand_b the low 62 bits of bytes 8 to 15.
+		a := uint64(u[6]&0x0f)<<8 | uint64(u[7])
+		b := binary.BigEndian.Uint64(u[8:])&(1<<62-1) + 1
+		if b == 1<<62 {
+			b = 0
+			a++
+		}
+		if a == 1<<12 {
+			// The counter is exhausted, continue in the next millisecond.
+			ms++
+		} else {
+			u[6], u[7] = byte(a>>8), byte(a)
+			binary.BigEndian.PutUint64(u[8:], b)
+		}
+	}
+	if ms != uuidV7.ms || uuidV7.ms == 0 {
+		if _, err := io.ReadFull(r, u[6:]); err != nil {
+			return UUID{}, err
+		}
+	}
+	if ms >= 1<<48 {
+		return UUID{}, fmt.Errorf("UUIDv7 timestamp out of range.")
+	}
+	u[0], u[1], u[2], u[3], u[4], u[5] = byte(ms>>40), byte(ms>>32), byte(ms>>24), byte(ms>>16), byte(ms>>8), byte(ms)
+	u[6] = u[6]&0x0f | 0x70
+	u[8] = u[8]&0x3f | 0x80
+	uuidV7.ms, uuidV7.last = ms, u
+	return u, nil
+}
+
+// ParseUUID returns the UUID s in the canonical form xxxxxxxx-xxxx-xxxx-xxxx-xxxxxxxxxxxx or as 32 hexadecimal digits.
+func ParseUUID(s string) (UUID, error) {
+	var u UUID
+	switch len(s) {
+	case 36:
+		if s[8] != '-' || s[13] != '-' || s[18] != '-' || s[23] != '-' {
+			return UUID{}, fmt.Errorf("Illegal UUID format %q.", s)
+		}
+		s = s[:8] + s[9:13] + s[14:18] + s[19:23] + s[24:]
+	case 32:
+	default:
+		return UUID{}, fmt.Errorf("Illegal UUID length %d.", len(s))
+	}
+	if _, err := hex.Decode(u[:], []byte(s)); err != nil {
+		return UUID{}, fmt.Errorf("Illegal UUID format %q.", s)
+	}
+	return u, nil
+}
+
+// String returns u in the canonical form xxxxxxxx-xxxx-xxxx-xxxx-xxxxxxxxxxxx.
+func (u UUID) String() string {
+	var b [36]byte
+	hex.Encode(b[:], u[:4])
+	b[8] = '-'
+	hex.Encode(b[9:], u[4:6])
+	b[13] = '-'
+	hex.Encode(b[14:], u[6:8])
+	b[18] = '-'
+	hex.Encode(b[19:], u[8:10])
+	b[23] = '-'
+	hex.Encode(b[24:], u[10:])
+	return string(b[:])
+}
+
+// Time returns the timestamp of a version 7 UUID.
+func (u UUID) Time() time.Time {
+	ms := int64(u[0])<<40 | int64(u[1])<<32 | int64(u[2])<<24 | int64(u[3])<<16 | int64(u[4])<<8 | int64(u[5])
+	return time.UnixMilli(ms)
+}
+
+// Version returns the version field of u.
+func (u UUID) Version() int {
+	return int(u[6] >> 4)
+}
@@ -0,0 +1,76 @@
+package base_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/7i/base"
+)
+
+func TestParseUUID(t *testing.T) {
+	for _, s := range []string{"6ba7b810-9dad-11d1-80b4-00c04fd430c8", "6BA7B8109DAD11D180B400C04FD430C8"} {
+		u, err := base.ParseUUID(s)
+		if err != nil || u.String() != "6ba7b810-9dad-11d1-80b4-00c04fd430c8" {
+			t.Errorf("ParseUUID(%q) got: %v %v.", s, u, err)
+		}
+		if u.Version() != 1 {
+			t.Errorf("ParseUUID(%q) got version %d expected 1.", s, u.Version())
+		}
+	}
+	for _, s := range []string{"", "6ba7b810-9dad-11d1-80b4_00c04fd430c8", "6ba7b8109dad11d180b400c04fd430cg"} {
+		if _, err := base.ParseUUID(s); err == nil {
+			t.Errorf("ParseUUID(%q) succeeded, expected an error.", s)
+		}
+	}
+}
+
+func TestNewUUIDv4(t *testing.T) {
+	u, err := base.NewUUIDv4()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if u.Version() != 4 || u[8]>>6 != 2 {
+		t.Errorf("NewUUIDv4 got %v with wrong version or variant.", u)
+	}
+}
+
+type fillReader byte
+
+func (r fillReader) Read(b []byte) (int, error) {
+	for i := range b {
+		b[i] = byte(r)
+	}
+	return len(b), nil
+}
+
+func TestNewUUIDv7(t *testing.T) {
+	var prev base.UUID
+	for i := 0; i < 1000; i++ {
+		u, err := base.NewUUIDv7()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if u.Version() != 7 || u[8]>>6 != 2 {
+			t.Fatalf("NewUUIDv7 got %v with wrong version or variant.", u)
+		}
+		if u.String() <= prev.String() {
+			t.Fatalf("NewUUIDv7 got %v after %v, expected strictly increasing UUIDs.", u, prev)
+		}
+		if d := time.Since(u.Time()); d < -time.Second || d > time.Minute {
+			t.Errorf("NewUUIDv7 got %v with timestamp %v.", u, u.Time())
+		}
+		prev = u
+	}
+
+	// An exhausted counter moves on to the next millisecond
+	ms := time.Now().UnixMilli() + 100
+	u1, _ := base.NewUUIDv7At(fillReader(0xff), ms)
+	u2, _ := base.NewUUIDv7At(fillReader(0), ms)
+	u3, _ := base.NewUUIDv7At(fillReader(0), ms-50)
+	if u1.Time().UnixMilli() != ms || u2.Time().UnixMilli() != ms+1 || u3.Time().UnixMilli() != ms+1 {
+		t.Errorf("NewUUIDv7 got timestamps %v %v %v, expected %d, %d and %d.", u1.Time(), u2.Time(), u3.Time(), ms, ms+1, ms+1)
+	}
+	if !(u1.String() < u2.String() && u2.String() < u3.String()) {
+		t.Errorf("NewUUIDv7 got %v %v %v, expected strictly increasing UUIDs.", u1, u2, u3)
+	}
+}